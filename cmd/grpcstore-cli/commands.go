@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+	"github.com/IlyaFloppy/grpcstore/sdk/client"
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // errors are reported by exitCode.
+	return fs
+}
+
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	err := fs.Parse(args)
+	if err != nil {
+		return usageError{fmt.Sprintf("%s: %v", fs.Name(), err)}
+	}
+	if fs.NArg() < minArgs || fs.NArg() > maxArgs {
+		return usageError{fmt.Sprintf("%s: unexpected number of arguments", fs.Name())}
+	}
+
+	return nil
+}
+
+func get(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("get")
+	err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.client.Get(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return c.out.item(record{
+		Key:     fs.Arg(0),
+		Value:   item.Value,
+		Version: item.Version,
+		Flags:   item.Flags,
+	})
+}
+
+func set(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("set")
+	ttl := fs.Duration("ttl", 0, "time to live, zero means no expiration")
+	flags := fs.Uint("flags", 0, "opaque flags stored with the value")
+	mode := fs.String("mode", "set", "set, add to fail if the key exists or replace to fail if it does not")
+	file := fs.String("file", "", "read value from the file, - means stdin")
+	err := parse(fs, args, 1, 2)
+	if err != nil {
+		return err
+	}
+
+	opts := []client.SetOption{client.WithTTL(*ttl), client.WithFlags(uint32(*flags))}
+	switch *mode {
+	case "set":
+	case "add":
+		opts = append(opts, client.IfNotExists())
+	case "replace":
+		opts = append(opts, client.IfExists())
+	default:
+		return usageError{fmt.Sprintf("set: unknown mode %q", *mode)}
+	}
+
+	var value []byte
+	switch {
+	case fs.NArg() == 2 && *file != "":
+		return usageError{"set: value and -file are mutually exclusive"}
+	case fs.NArg() == 2:
+		value = []byte(fs.Arg(1))
+	case *file != "" && *file != "-":
+		value, err = os.ReadFile(*file)
+	default:
+		value, err = io.ReadAll(c.stdin)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read value: %w", err)
+	}
+
+	return c.client.Set(ctx, fs.Arg(0), value, opts...)
+}
+
+func del(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("delete")
+	err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Delete(ctx, fs.Arg(0))
+}
+
+func list(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("list")
+	prefix := fs.String("prefix", "", "list only keys that start with prefix")
+	values := fs.Bool("values", false, "print values")
+	pageSize := fs.Uint("page-size", 100, "number of keys requested at once")
+	err := parse(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ListRequest{
+		Prefix:        *prefix,
+		PageSize:      uint32(*pageSize),
+		IncludeValues: *values,
+	}
+	for {
+		res, err := c.listPage(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range res.GetItems() {
+			err = c.out.listed(record{
+				Key:     item.GetKey(),
+				Value:   item.GetValue(),
+				Version: item.GetVersion(),
+				Flags:   item.GetFlags(),
+			}, *values)
+			if err != nil {
+				return err
+			}
+		}
+
+		if res.GetNextPageToken() == "" {
+			return nil
+		}
+		req.PageToken = res.GetNextPageToken()
+	}
+}
+
+func (c *cli) listPage(ctx context.Context, req *pb.ListRequest) (*pb.ListResult, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	return c.client.GRPC().List(ctx, req)
+}
+
+func watch(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("watch")
+	prefix := fs.Bool("prefix", false, "watch all keys that start with key")
+	err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.GRPC().Watch(ctx, &pb.WatchRequest{Key: fs.Arg(0), Prefix: *prefix})
+	if err != nil {
+		return err
+	}
+
+	for {
+		e, err := stream.Recv()
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil // interrupted by user.
+		}
+		if err != nil {
+			return err
+		}
+
+		err = c.out.event(record{
+			Type:  eventType(e.GetType()),
+			Key:   e.GetKey(),
+			Value: e.GetValue(),
+			Flags: e.GetFlags(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func eventType(t pb.WatchEvent_Type) string {
+	switch t {
+	case pb.WatchEvent_TYPE_PUT:
+		return "PUT"
+	case pb.WatchEvent_TYPE_DELETE:
+		return "DELETE"
+	case pb.WatchEvent_TYPE_EXPIRE:
+		return "EXPIRE"
+	case pb.WatchEvent_TYPE_UNSPECIFIED:
+	}
+
+	return "UNSPECIFIED"
+}
@@ -0,0 +1,160 @@
+// Command grpcstore-cli reads and changes keys of a running grpcstore server.
+//
+//	grpcstore-cli [-addr host:port] [-output text|hex|json] <get|set|delete|list|watch> [flags] [args]
+//
+// Exit code is 0 on success, 1 on errors without grpc status, 2 on invalid usage and 10 plus the grpc status code
+// otherwise, e.g. 15 when the key is not found and 24 when the server is unavailable.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/IlyaFloppy/grpcstore/sdk/client"
+)
+
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitStatusBase = 10
+
+	envAddr  = "GRPCSTORE_ADDR"
+	envToken = "GRPCSTORE_TOKEN"
+
+	defaultAddr = "localhost:4242"
+)
+
+const usage = `Usage: grpcstore-cli [flags] <command> [command flags] [args]
+
+Commands:
+  get <key>                                          print value of the key
+  set [-ttl d] [-flags n] [-mode m] [-file f] <key> [value]
+                                                     set the key to value, contents of file f or stdin
+  delete <key>                                       delete the key
+  list [-prefix p] [-values] [-page-size n]          list keys
+  watch [-prefix] <key>                              print changes of the key until interrupted
+
+Flags:
+`
+
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+type cli struct {
+	client  *client.Client
+	timeout time.Duration
+	out     printer
+	stdin   io.Reader
+}
+
+type command func(ctx context.Context, c *cli, args []string) error
+
+var commands = map[string]command{
+	"get":    get,
+	"set":    set,
+	"delete": del,
+	"list":   list,
+	"watch":  watch,
+}
+
+func run(ctx context.Context, args []string, getenv func(string) string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("grpcstore-cli", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	addr := getenv(envAddr)
+	if addr == "" {
+		addr = defaultAddr
+	}
+	fs.StringVar(&addr, "addr", addr, "server address, defaults to $"+envAddr)
+	token := fs.String("token", getenv(envToken), "bearer token, defaults to $"+envToken)
+	useTLS := fs.Bool("tls", false, "connect with TLS")
+	timeout := fs.Duration("timeout", 5*time.Second, "timeout of a call")
+	output := fs.String("output", formatText, "output format: text, hex or json")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+	if !validFormat(*output) {
+		fmt.Fprintf(stderr, "unknown output format %q\n", *output)
+		return exitUsage
+	}
+
+	opts := []client.Option{client.WithTimeout(*timeout)}
+	if *token != "" {
+		opts = append(opts, client.WithBearerToken(*token))
+	}
+	if *useTLS {
+		opts = append(opts, client.WithTLS(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	c, err := client.New(addr, opts...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	defer c.Close()
+
+	err = cmd(ctx, &cli{
+		client:  c,
+		timeout: *timeout,
+		out:     printer{w: stdout, format: *output},
+		stdin:   stdin,
+	}, fs.Args()[1:])
+
+	return exitCode(stderr, err)
+}
+
+func exitCode(stderr io.Writer, err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintln(stderr, err)
+
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+	if st, ok := status.FromError(err); ok {
+		return exitStatusBase + int(st.Code())
+	}
+
+	return exitError
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Getenv, os.Stdin, os.Stdout, os.Stderr)
+	cancel()
+
+	os.Exit(code)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/server"
+	"github.com/IlyaFloppy/grpcstore/internal/storage/inmemory"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+// syncBuffer is written by watch while test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := server.New(zerolog.Nop(), config.ServerConfig{}, inmemory.New(config.InMemoryStorageConfig{}), nil)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGRPCStoreServiceServer(grpcServer, srv)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestCLI(t *testing.T) {
+	t.Cleanup(func() {
+		goleak.VerifyNone(t) // after the server is stopped.
+	})
+
+	addr := startServer(t)
+	env := func(key string) string {
+		if key == envAddr {
+			return addr
+		}
+		return ""
+	}
+	cli := func(stdin string, args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), args, env, strings.NewReader(stdin), &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	t.Run("get set delete", func(t *testing.T) {
+		code, _, stderr := cli("", "get", "key")
+		require.Equal(t, 15, code, stderr)
+
+		code, _, stderr = cli("", "set", "-flags", "7", "key", "value")
+		require.Equal(t, exitOK, code, stderr)
+
+		code, stdout, _ := cli("", "get", "key")
+		require.Equal(t, exitOK, code)
+		require.Equal(t, "value\n", stdout)
+
+		code, stdout, _ = cli("", "-output", "hex", "get", "key")
+		require.Equal(t, exitOK, code)
+		require.Equal(t, hex.EncodeToString([]byte("value"))+"\n", stdout)
+
+		code, stdout, _ = cli("", "-output", "json", "get", "key")
+		require.Equal(t, exitOK, code)
+		require.Regexp(t, `^{"key":"key","value":"dmFsdWU=","version":\d+,"flags":7}\n$`, stdout)
+
+		code, _, _ = cli("", "set", "-mode", "add", "key", "value")
+		require.Equal(t, 16, code) // AlreadyExists.
+
+		code, _, _ = cli("", "delete", "key")
+		require.Equal(t, exitOK, code)
+		code, _, _ = cli("", "get", "key")
+		require.Equal(t, 15, code)
+	})
+
+	t.Run("set from stdin and file", func(t *testing.T) {
+		code, _, _ := cli("from stdin", "set", "stdin")
+		require.Equal(t, exitOK, code)
+		_, stdout, _ := cli("", "get", "stdin")
+		require.Equal(t, "from stdin\n", stdout)
+
+		path := filepath.Join(t.TempDir(), "value")
+		require.NoError(t, os.WriteFile(path, []byte("from file"), 0o600))
+		code, _, _ = cli("", "set", "-file", path, "file")
+		require.Equal(t, exitOK, code)
+		_, stdout, _ = cli("", "get", "file")
+		require.Equal(t, "from file\n", stdout)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		for _, key := range []string{"list/a", "list/b", "list/c"} {
+			code, _, _ := cli("", "set", key, key)
+			require.Equal(t, exitOK, code)
+		}
+
+		code, stdout, _ := cli("", "list", "-prefix", "list/", "-page-size", "2")
+		require.Equal(t, exitOK, code)
+		require.Equal(t, "list/a\nlist/b\nlist/c\n", stdout)
+
+		code, stdout, _ = cli("", "list", "-prefix", "list/a", "-values")
+		require.Equal(t, exitOK, code)
+		require.Equal(t, "list/a\tlist/a\n", stdout)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var stdout syncBuffer
+		done := make(chan int)
+		go func() {
+			done <- run(ctx, []string{"watch", "-prefix", "watch/"}, env, nil, &stdout, &stdout)
+		}()
+
+		require.Eventually(t, func() bool {
+			code, _, _ := cli("", "set", "watch/a", "value")
+			require.Equal(t, exitOK, code)
+			return strings.Contains(stdout.String(), "PUT\twatch/a\tvalue\n")
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		require.Equal(t, exitOK, <-done)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"unknown"},
+			{"get"},
+			{"get", "a", "b"},
+			{"-output", "xml", "get", "key"},
+			{"set", "-mode", "cas", "key", "value"},
+			{"set", "-file", "path", "key", "value"},
+		} {
+			code, _, _ := cli("", args...)
+			require.Equal(t, exitUsage, code, args)
+		}
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"-addr", "127.0.0.1:1", "-timeout", "100ms", "get", "key"},
+			env, nil, &stdout, &stderr)
+		require.Contains(t, []int{24, 14}, code, stderr.String()) // Unavailable or DeadlineExceeded.
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	formatText = "text"
+	formatHex  = "hex"
+	formatJSON = "json"
+)
+
+func validFormat(format string) bool {
+	return format == formatText || format == formatHex || format == formatJSON
+}
+
+// record is printed as a JSON object per line in json format, value is base64 encoded there.
+type record struct {
+	Type    string `json:"type,omitempty"`
+	Key     string `json:"key"`
+	Value   []byte `json:"value,omitempty"`
+	Version uint64 `json:"version,omitempty"`
+	Flags   uint32 `json:"flags,omitempty"`
+}
+
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// item prints only the value in text and hex formats, so that it can be piped to other commands.
+func (p printer) item(r record) error {
+	if p.format == formatJSON {
+		return p.json(r)
+	}
+
+	return p.line(p.value(r.Value))
+}
+
+// listed prints key and optionally value separated by a tab in text and hex formats.
+func (p printer) listed(r record, withValue bool) error {
+	if p.format == formatJSON {
+		return p.json(r)
+	}
+	if !withValue {
+		return p.line(r.Key)
+	}
+
+	return p.line(r.Key, p.value(r.Value))
+}
+
+// event prints type, key and value separated by tabs in text and hex formats.
+func (p printer) event(r record) error {
+	if p.format == formatJSON {
+		return p.json(r)
+	}
+	if len(r.Value) == 0 {
+		return p.line(r.Type, r.Key)
+	}
+
+	return p.line(r.Type, r.Key, p.value(r.Value))
+}
+
+func (p printer) value(v []byte) string {
+	if p.format == formatHex {
+		return hex.EncodeToString(v)
+	}
+
+	return string(v)
+}
+
+func (p printer) line(fields ...string) error {
+	for i, f := range fields {
+		sep := "\t"
+		if i == len(fields)-1 {
+			sep = "\n"
+		}
+
+		_, err := fmt.Fprint(p.w, f, sep)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p printer) json(r record) error {
+	return json.NewEncoder(p.w).Encode(r)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/gateway"
+	"github.com/IlyaFloppy/grpcstore/internal/lease"
 	"github.com/IlyaFloppy/grpcstore/internal/server"
 	"github.com/IlyaFloppy/grpcstore/internal/storage/inmemory"
 	"github.com/IlyaFloppy/grpcstore/internal/storage/memcached"
@@ -28,7 +30,9 @@ type registry struct {
 		server.IStorage
 		componentor.Component
 	}
-	server *server.Server
+	leases  *lease.Manager
+	server  *server.Server
+	gateway *gateway.Gateway
 }
 
 func run() int {
@@ -54,18 +58,26 @@ func run() int {
 	if r.config.StorageConfig.UseMemcached {
 		r.storage = memcached.New(r.config.StorageConfig.MemcachedStorageConfig)
 	} else {
-		r.storage = inmemory.New()
+		r.storage = inmemory.New(r.config.StorageConfig.InMemoryStorageConfig)
 	}
 
-	r.server = server.New(r.logger, r.config.ServerConfig, r.storage)
+	r.leases = lease.New(r.logger, r.config.LeaseConfig, r.storage)
+	r.server = server.New(r.logger, r.config.ServerConfig, r.storage, r.leases)
+
+	components := []componentor.Component{
+		r.storage,
+		r.leases,
+		r.server,
+	}
+	if r.config.GatewayConfig.Enabled {
+		r.gateway = gateway.New(r.logger, r.config.GatewayConfig, r.server)
+		components = append(components, r.gateway)
+	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	code := componentor.Run(ctx, r.logger, []componentor.Component{
-		r.storage,
-		r.server,
-	})
+	code := componentor.Run(ctx, r.logger, components)
 
 	return code
 }
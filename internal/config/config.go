@@ -6,6 +6,8 @@ type Config struct {
 	LoggerConfig  LoggerConfig  `yaml:"logger"`
 	ServerConfig  ServerConfig  `yaml:"server"`
 	StorageConfig StorageConfig `yaml:"storage"`
+	LeaseConfig   LeaseConfig   `yaml:"lease"`
+	GatewayConfig GatewayConfig `yaml:"gateway"`
 }
 
 type LoggerConfig struct {
@@ -19,6 +21,15 @@ type ServerConfig struct {
 	KeepAliveTimeout time.Duration `yaml:"keep_alive_timeout"`
 	WriteBufferSize  int           `yaml:"write_buffer_size"`
 	ReadBufferSize   int           `yaml:"read_buffer_size"`
+
+	// PipelineConcurrency limits concurrent operations of a single Pipeline stream when storage has no own limit.
+	PipelineConcurrency int `yaml:"pipeline_concurrency"`
+
+	// HealthCheckInterval is how often storage is checked to report the health of the server.
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+
+	// Reflection enables grpc server reflection, so that tools like grpcurl work without .proto files.
+	Reflection bool `yaml:"reflection"`
 }
 
 type StorageConfig struct {
@@ -27,10 +38,22 @@ type StorageConfig struct {
 	MemcachedStorageConfig MemcachedStorageConfig `yaml:"memcached"`
 }
 
-type InMemoryStorageConfig struct{}
+type InMemoryStorageConfig struct {
+	SweepInterval time.Duration `yaml:"sweep_interval"`
+}
 
 type MemcachedStorageConfig struct {
 	Address  string `yaml:"address"`
 	UsePool  bool   `yaml:"use_pool"`
 	PoolSize int    `yaml:"pool_size"`
 }
+
+type LeaseConfig struct {
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+type GatewayConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Address     string `yaml:"address"`
+	MaxBodySize int64  `yaml:"max_body_size"`
+}
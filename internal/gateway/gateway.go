@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+)
+
+const (
+	defaultMaxBodySize = 4 << 20 // same as default grpc message size limit.
+	readHeaderTimeout  = 10 * time.Second
+)
+
+// Gateway serves a subset of the store API over HTTP for clients that can not use grpc.
+type Gateway struct {
+	logger  zerolog.Logger
+	cfg     config.GatewayConfig
+	store   IStore
+	readyCh chan struct{}
+}
+
+func New(logger zerolog.Logger, cfg config.GatewayConfig, store IStore) *Gateway {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultMaxBodySize
+	}
+
+	return &Gateway{
+		logger:  logger.With().Str("component", (*Gateway)(nil).Name()).Logger(),
+		cfg:     cfg,
+		store:   store,
+		readyCh: make(chan struct{}),
+	}
+}
+
+func (g *Gateway) Name() string {
+	return "http-gateway"
+}
+
+func (g *Gateway) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", g.cfg.Address)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           g.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	shutdownCh := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCh <- srv.Shutdown(context.Background())
+	}()
+
+	g.logger.Info().Str("address", g.cfg.Address).Msg("gateway started listening")
+	close(g.readyCh)
+
+	err = srv.Serve(lis)
+	if errors.Is(err, http.ErrServerClosed) {
+		return <-shutdownCh // serve returns at once, shutdown waits for active requests.
+	}
+
+	return err
+}
+
+func (g *Gateway) ReadyCh() <-chan struct{} {
+	return g.readyCh
+}
@@ -0,0 +1,113 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/server"
+	"github.com/IlyaFloppy/grpcstore/internal/storage/inmemory"
+)
+
+func TestGateway(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	logger := zerolog.New(os.Stderr)
+	store := server.New(logger, config.ServerConfig{}, inmemory.New(config.InMemoryStorageConfig{}), nil)
+	handler := New(logger, config.GatewayConfig{MaxBodySize: 16}, store).Handler()
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+	requireCode := func(t *testing.T, rec *httptest.ResponseRecorder, httpCode int, code codes.Code) {
+		t.Helper()
+		require.Equal(t, httpCode, rec.Code, rec.Body.String())
+
+		var st struct {
+			Code    codes.Code        `json:"code"`
+			Details []json.RawMessage `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &st))
+		require.Equal(t, code, st.Code)
+	}
+
+	t.Run("raw value", func(t *testing.T) {
+		rec := do(http.MethodPut, "/v1/keys/users/1?flags=7&ttl=1m", "12345")
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+		rec = do(http.MethodGet, "/v1/keys/users/1", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "12345", rec.Body.String())
+		require.Equal(t, "7", rec.Header().Get("X-Flags"))
+		require.NotEmpty(t, rec.Header().Get("X-Version"))
+
+		rec = do(http.MethodHead, "/v1/keys/users/1", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "5", rec.Header().Get("Content-Length"))
+		require.Empty(t, rec.Body.String())
+	})
+
+	t.Run("base64 value", func(t *testing.T) {
+		rec := do(http.MethodPut, "/v1/keys/binary?encoding=base64", "AAEC/w==\n")
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+		rec = do(http.MethodGet, "/v1/keys/binary", "")
+		require.Equal(t, []byte{0, 1, 2, 255}, rec.Body.Bytes())
+
+		rec = do(http.MethodGet, "/v1/keys/binary?encoding=base64", "")
+		require.Equal(t, "AAEC/w==", rec.Body.String())
+
+		rec = do(http.MethodPut, "/v1/keys/binary?encoding=base64", "not base64")
+		requireCode(t, rec, http.StatusBadRequest, codes.InvalidArgument)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rec := do(http.MethodPut, "/v1/keys/deleted", "1")
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+		rec = do(http.MethodDelete, "/v1/keys/deleted", "")
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+		rec = do(http.MethodGet, "/v1/keys/deleted", "")
+		requireCode(t, rec, http.StatusNotFound, codes.NotFound)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		requireCode(t, do(http.MethodGet, "/v1/keys/missing", ""), http.StatusNotFound, codes.NotFound)
+		requireCode(t, do(http.MethodPut, "/v1/keys/users/1?mode=add", "1"), http.StatusConflict, codes.AlreadyExists)
+		requireCode(t, do(http.MethodPut, "/v1/keys/key?ttl=soon", "1"), http.StatusBadRequest, codes.InvalidArgument)
+		requireCode(t, do(http.MethodPut, "/v1/keys/key?ttl=-1s", "1"), http.StatusBadRequest, codes.InvalidArgument)
+		requireCode(t, do(http.MethodPut, "/v1/keys/key", strings.Repeat("1", 17)),
+			http.StatusRequestEntityTooLarge, codes.InvalidArgument)
+		requireCode(t, do(http.MethodPost, "/v1/keys/key", "1"), http.StatusMethodNotAllowed, codes.Unimplemented)
+		requireCode(t, do(http.MethodGet, "/v1/keys/", ""), http.StatusBadRequest, codes.InvalidArgument)
+	})
+}
+
+func TestRun(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	gateway := New(zerolog.New(os.Stderr), config.GatewayConfig{Address: "127.0.0.1:0"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- gateway.Run(ctx)
+	}()
+	<-gateway.ReadyCh()
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
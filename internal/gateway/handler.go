@@ -0,0 +1,244 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+const keysPath = "/v1/keys/"
+
+// Handler serves GET, HEAD, PUT and DELETE on /v1/keys/{key}, key is the rest of the path and may contain slashes.
+// Values are sent as is, or base64 encoded with ?encoding=base64. PUT accepts ttl (e.g. 1m30s), flags and mode (set,
+// add or replace) query parameters. Errors are google.rpc.Status in JSON with a HTTP code that matches the grpc code.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(keysPath, g.handleKey)
+
+	return g.withLogging(mux)
+}
+
+func (g *Gateway) handleKey(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, keysPath)
+	if key == "" {
+		g.writeError(w, status.Error(codes.InvalidArgument, "key must not be empty"))
+		return
+	}
+
+	var encoded bool
+	switch r.URL.Query().Get("encoding") {
+	case "", "raw":
+	case "base64":
+		encoded = true
+	default:
+		g.writeError(w, status.Error(codes.InvalidArgument, "encoding must be raw or base64"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		g.get(w, r, key, encoded)
+	case http.MethodPut:
+		g.put(w, r, key, encoded)
+	case http.MethodDelete:
+		g.delete(w, r, key)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		g.writeStatus(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method is not allowed"))
+	}
+}
+
+func (g *Gateway) get(w http.ResponseWriter, r *http.Request, key string, encoded bool) {
+	res, err := g.store.Get(r.Context(), &pb.GetRequest{Key: key})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	value := res.GetValue()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if encoded {
+		value = []byte(base64.StdEncoding.EncodeToString(value))
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(value)))
+	w.Header().Set("X-Version", strconv.FormatUint(res.GetVersion(), 10))
+	w.Header().Set("X-Flags", strconv.FormatUint(uint64(res.GetFlags()), 10))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(value)
+	}
+}
+
+func (g *Gateway) put(w http.ResponseWriter, r *http.Request, key string, encoded bool) {
+	req, err := parseSetRequest(r)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	req.Key = key
+
+	value, err := io.ReadAll(io.LimitReader(r.Body, g.cfg.MaxBodySize+1))
+	if err != nil {
+		g.writeError(w, status.Errorf(codes.InvalidArgument, "failed to read body: %s", err.Error()))
+		return
+	}
+	if int64(len(value)) > g.cfg.MaxBodySize {
+		g.writeStatus(w, http.StatusRequestEntityTooLarge,
+			status.Newf(codes.InvalidArgument, "body must not be larger than %d bytes", g.cfg.MaxBodySize))
+		return
+	}
+
+	if encoded {
+		value, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(value)))
+		if err != nil {
+			g.writeError(w, status.Error(codes.InvalidArgument, "body is not valid base64"))
+			return
+		}
+	}
+	req.Value = value
+
+	_, err = g.store.Set(r.Context(), req)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *Gateway) delete(w http.ResponseWriter, r *http.Request, key string) {
+	_, err := g.store.Delete(r.Context(), &pb.DeleteRequest{Key: key})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseSetRequest parses query parameters of PUT, key and value are set by the caller.
+func parseSetRequest(r *http.Request) (*pb.SetRequest, error) {
+	query := r.URL.Query()
+	req := &pb.SetRequest{}
+
+	if v := query.Get("ttl"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %s", err.Error())
+		}
+		req.Ttl = durationpb.New(ttl)
+	}
+
+	if v := query.Get("flags"); v != "" {
+		flags, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid flags: %s", err.Error())
+		}
+		req.Flags = uint32(flags)
+	}
+
+	switch query.Get("mode") {
+	case "", "set":
+		req.Mode = pb.SetMode_SET_MODE_SET
+	case "add":
+		req.Mode = pb.SetMode_SET_MODE_ADD
+	case "replace":
+		req.Mode = pb.SetMode_SET_MODE_REPLACE
+	default:
+		return nil, status.Error(codes.InvalidArgument, "mode must be set, add or replace")
+	}
+
+	return req, nil
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	g.writeStatus(w, httpStatus(st.Code()), st)
+}
+
+func (g *Gateway) writeStatus(w http.ResponseWriter, code int, st *status.Status) {
+	body, err := protojson.Marshal(st.Proto())
+	if err != nil {
+		g.logger.Err(err).Msg("failed to marshal status")
+		body = []byte(`{"code":13,"message":"failed to marshal status"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// httpStatus maps grpc codes to HTTP the same way grpc-gateway does.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // client closed request, nginx extension.
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unknown, codes.Internal, codes.DataLoss:
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusInternalServerError
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (g *Gateway) withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		event := g.logger.Info()
+		if rec.code >= http.StatusInternalServerError {
+			event = g.logger.Error()
+		}
+		event.
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("code", rec.code).
+			Dur("duration", time.Since(start)).
+			Msg("http request finished")
+	})
+}
@@ -0,0 +1,15 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+// IStore is implemented by server.Server. Calls are made in process, so the gateway shares storage, watchers and
+// error handling with grpc clients.
+type IStore interface {
+	Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResult, error)
+	Set(ctx context.Context, req *pb.SetRequest) (*pb.SetResult, error)
+	Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResult, error)
+}
@@ -0,0 +1,9 @@
+package lease
+
+import "errors"
+
+var ErrNotFound = notFoundError{errors.New("lease not found")}
+
+type notFoundError struct{ error }
+
+func (notFoundError) NotFoundErrorMarker() {}
@@ -0,0 +1,5 @@
+package lease
+
+type IStorage interface {
+	Delete(key string) error
+}
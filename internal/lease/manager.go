@@ -0,0 +1,252 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+)
+
+const (
+	defaultCheckInterval = time.Second
+
+	// keyStripes is the number of mutexes that serialize writes of keys with their deletion, keys are hashed to them.
+	keyStripes = 256
+)
+
+type lease struct {
+	ttl       time.Duration
+	expiresAt time.Time
+	keys      map[string]struct{}
+}
+
+// Manager keeps leases in memory and deletes keys attached to a lease when it expires or is revoked.
+type Manager struct {
+	logger  zerolog.Logger
+	cfg     config.LeaseConfig
+	storage IStorage
+	readyCh chan struct{}
+
+	mu       sync.Mutex
+	lastID   int64
+	leases   map[int64]*lease
+	keys     map[string]int64 // lease of every attached key, including dropped leases whose keys are not deleted yet.
+	onExpire func(key string)
+
+	keyLocks [keyStripes]sync.Mutex // held while a key is written and attached or checked and deleted.
+}
+
+func New(logger zerolog.Logger, cfg config.LeaseConfig, storage IStorage) *Manager {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = defaultCheckInterval
+	}
+
+	return &Manager{
+		logger:  logger.With().Str("component", (*Manager)(nil).Name()).Logger(),
+		cfg:     cfg,
+		storage: storage,
+		readyCh: make(chan struct{}),
+		leases:  make(map[int64]*lease),
+		keys:    make(map[string]int64),
+	}
+}
+
+func (m *Manager) Name() string {
+	return "lease-manager"
+}
+
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	close(m.readyCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			m.expire(now)
+		}
+	}
+}
+
+func (m *Manager) ReadyCh() <-chan struct{} {
+	return m.readyCh
+}
+
+// OnExpire registers fn to be called for every key deleted because its lease has expired.
+func (m *Manager) OnExpire(fn func(key string)) {
+	m.mu.Lock()
+	m.onExpire = fn
+	m.mu.Unlock()
+}
+
+func (m *Manager) Grant(ttl time.Duration) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	m.leases[m.lastID] = &lease{
+		ttl:       ttl,
+		expiresAt: time.Now().Add(ttl),
+		keys:      make(map[string]struct{}),
+	}
+
+	return m.lastID
+}
+
+// Revoke deletes the lease and all its keys and returns the deleted keys.
+func (m *Manager) Revoke(id int64) ([]string, error) {
+	m.mu.Lock()
+	l, ok := m.leases[id]
+	if ok {
+		delete(m.leases, id)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return m.deleteKeys(id, l), nil
+}
+
+// KeepAlive resets expiration time of the lease and returns its ttl.
+func (m *Manager) KeepAlive(id int64) (time.Duration, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.leases[id]
+	if !ok || !now.Before(l.expiresAt) { // expired lease waits for the next check to delete its keys.
+		return 0, ErrNotFound
+	}
+
+	l.expiresAt = now.Add(l.ttl)
+
+	return l.ttl, nil
+}
+
+// Exists reports whether the lease is granted and not expired.
+func (m *Manager) Exists(id int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.leases[id]
+	return ok && time.Now().Before(l.expiresAt)
+}
+
+// LockKey makes writing key and attaching it to a lease atomic with respect to deleting it along with its previous
+// lease, so that a key rewritten in between is not deleted. The key must not be locked again before calling the
+// returned unlock.
+func (m *Manager) LockKey(key string) (unlock func()) {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	mu := &m.keyLocks[hash.Sum32()%keyStripes]
+	mu.Lock()
+
+	return mu.Unlock
+}
+
+// Attach moves key to the lease, detaching it from the previous one.
+func (m *Manager) Attach(id int64, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.leases[id]
+	if !ok || !time.Now().Before(l.expiresAt) {
+		return ErrNotFound
+	}
+
+	m.detach(key)
+	l.keys[key] = struct{}{}
+	m.keys[key] = id
+
+	return nil
+}
+
+func (m *Manager) Detach(key string) {
+	m.mu.Lock()
+	m.detach(key)
+	m.mu.Unlock()
+}
+
+// detach must be called with mu locked. Detaching a key of a dropped lease keeps it from being deleted.
+func (m *Manager) detach(key string) {
+	id, ok := m.keys[key]
+	if !ok {
+		return
+	}
+
+	delete(m.keys, key)
+	if l, ok := m.leases[id]; ok {
+		delete(l.keys, key)
+	}
+}
+
+func (m *Manager) expire(now time.Time) {
+	expired := make(map[int64]*lease)
+
+	m.mu.Lock()
+	for id, l := range m.leases {
+		if !now.Before(l.expiresAt) {
+			delete(m.leases, id)
+			expired[id] = l
+		}
+	}
+	onExpire := m.onExpire
+	m.mu.Unlock()
+
+	for id, l := range expired {
+		for _, key := range m.deleteKeys(id, l) {
+			if onExpire != nil {
+				onExpire(key)
+			}
+		}
+	}
+}
+
+// deleteKeys deletes keys of the dropped lease id. It is called without mu locked as storage can be slow, keys of a
+// dropped lease are not changed by others. Keys that failed to be deleted are logged and skipped.
+func (m *Manager) deleteKeys(id int64, l *lease) []string {
+	keys := make([]string, 0, len(l.keys))
+	for key := range l.keys {
+		if m.deleteKey(id, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// deleteKey deletes key unless it was written again with another lease or without one after the lease id was dropped.
+// The check and the delete are made under the key lock, so such a write can not happen in between.
+func (m *Manager) deleteKey(id int64, key string) bool {
+	defer m.LockKey(key)()
+
+	m.mu.Lock()
+	owner, ok := m.keys[key]
+	if ok && owner == id {
+		delete(m.keys, key)
+	}
+	m.mu.Unlock()
+	if !ok || owner != id {
+		return false
+	}
+
+	var notFound interface{ NotFoundErrorMarker() }
+	err := m.storage.Delete(key)
+	if err != nil && !errors.As(err, &notFound) {
+		m.logger.Error().Err(err).Str("key", key).Msg("failed to delete key of a lease")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,106 @@
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/internal/storage/inmemory"
+)
+
+func TestManager(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := inmemory.New(config.InMemoryStorageConfig{})
+	m := New(zerolog.Nop(), config.LeaseConfig{CheckInterval: time.Millisecond}, s)
+
+	var expired []string
+	m.OnExpire(func(key string) {
+		expired = append(expired, key)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, m.Run(ctx))
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	<-m.ReadyCh()
+
+	short := m.Grant(50 * time.Millisecond)
+	long := m.Grant(time.Hour)
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, s.Set(key, []byte(key), 0, 0))
+	}
+	require.NoError(t, m.Attach(short, "a"))
+	require.NoError(t, m.Attach(short, "b"))
+	require.NoError(t, m.Attach(short, "c"))
+	require.NoError(t, m.Attach(long, "c")) // moves c to another lease.
+
+	ttl, err := m.KeepAlive(short)
+	require.NoError(t, err)
+	require.Equal(t, 50*time.Millisecond, ttl)
+
+	m.Detach("b")
+
+	require.Eventually(t, func() bool {
+		_, err := s.Get("a")
+		return err == storage.ErrNotFound
+	}, time.Second, time.Millisecond)
+
+	_, err = m.KeepAlive(short)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, m.Attach(short, "a"), ErrNotFound)
+	require.False(t, m.Exists(short))
+	require.True(t, m.Exists(long))
+
+	_, err = s.Get("b")
+	require.NoError(t, err, "detached key must survive")
+	_, err = s.Get("c")
+	require.NoError(t, err, "key of another lease must survive")
+
+	// d is rewritten with another lease while its lease is being revoked.
+	d := m.Grant(time.Hour)
+	require.NoError(t, s.Set("d", []byte("d"), 0, 0))
+	require.NoError(t, m.Attach(d, "d"))
+	unlock := m.LockKey("d")
+	revoked := make(chan []string)
+	go func() {
+		keys, err := m.Revoke(d)
+		require.NoError(t, err)
+		revoked <- keys
+	}()
+	require.Eventually(t, func() bool {
+		return !m.Exists(d)
+	}, time.Second, time.Millisecond)
+	require.NoError(t, s.Set("d", []byte("rewritten"), 0, 0))
+	require.NoError(t, m.Attach(long, "d"))
+	unlock()
+	require.Empty(t, <-revoked)
+	_, err = s.Get("d")
+	require.NoError(t, err, "rewritten key must survive")
+
+	keys, err := m.Revoke(long)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"c", "d"}, keys)
+	_, err = s.Get("c")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	_, err = m.Revoke(long)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	cancel()
+	<-done
+	require.Equal(t, []string{"a"}, expired)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+func (s *Server) GetDescriptor(ctx context.Context, req *pb.GetDescriptorRequest) (*pb.GetDescriptorResult, error) {
+	return &pb.GetDescriptorResult{
+		FileDescriptorSet: fileDescriptorSet(pb.File_grpcstore_proto),
+	}, nil
+}
+
+func (s *Server) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResult, error) {
+	now := time.Now()
+	res := &pb.GetStatsResult{
+		Uptime:  durationpb.New(now.Sub(s.calls.started)),
+		Methods: s.calls.snapshot(now),
+		Hits:    atomic.LoadUint64(&s.calls.hits),
+		Misses:  atomic.LoadUint64(&s.calls.misses),
+	}
+	if reads := res.Hits + res.Misses; reads > 0 {
+		res.HitRatio = float64(res.Hits) / float64(reads)
+	}
+	if s.statsReporter == nil {
+		return res, nil
+	}
+
+	stats, err := s.statsReporter.Stats()
+	if err != nil {
+		return nil, s.storageError(errCode(err), "", err, "failed to get storage stats")
+	}
+
+	res.KeyCount = stats.Keys
+	res.TotalBytes = stats.Bytes
+	res.BackendStats = stats.Raw
+
+	return res, nil
+}
+
+// fileDescriptorSet returns descriptors of file and of its transitive imports, every file goes after its imports.
+func fileDescriptorSet(file protoreflect.FileDescriptor) *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+
+	var add func(f protoreflect.FileDescriptor)
+	add = func(f protoreflect.FileDescriptor) {
+		if seen[f.Path()] {
+			return
+		}
+		seen[f.Path()] = true
+
+		imports := f.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(f))
+	}
+	add(file)
+
+	return set
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/reflect/protodesc"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+func TestGetDescriptor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	res, err := server.GetDescriptor(context.Background(), &pb.GetDescriptorRequest{})
+	require.NoError(t, err)
+
+	set := res.GetFileDescriptorSet()
+	require.Equal(t, "grpcstore.proto", set.GetFile()[len(set.GetFile())-1].GetName())
+
+	files, err := protodesc.NewFiles(set) // fails if a file goes before its imports or an import is missing.
+	require.NoError(t, err)
+	_, err = files.FindDescriptorByName("pb.GRPCStoreService")
+	require.NoError(t, err)
+	_, err = files.FindDescriptorByName("pb.AdminService")
+	require.NoError(t, err)
+}
+
+func TestReflection(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	for _, enabled := range []bool{false, true} {
+		storage := servermocks.NewMockIStorage(ctrl)
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{Address: "127.0.0.1:0", Reflection: enabled}, storage, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- server.Run(ctx)
+		}()
+		<-server.ReadyCh()
+
+		_, ok := server.grpcServer.GetServiceInfo()["grpc.reflection.v1alpha.ServerReflection"]
+		require.Equal(t, enabled, ok)
+
+		cancel()
+		require.NoError(t, <-errCh)
+	}
+}
@@ -2,47 +2,422 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
 	"github.com/IlyaFloppy/grpcstore/public-api/pb"
 )
 
+const (
+	defaultListPageSize = 100
+	maxListPageSize     = 1000
+
+	// reservedPrefix starts keys the server keeps for itself, they are not listed.
+	reservedPrefix = "__grpcstore/"
+)
+
 func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResult, error) {
-	data, err := s.storage.Get(req.GetKey())
+	item, err := s.storage.Get(req.GetKey())
+	s.calls.read(err)
 	if err != nil {
-		return nil, status.Errorf(errCode(err), "failed to get key: %s", err.Error())
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to get key")
 	}
 
 	return &pb.GetResult{
-		Value: data,
+		Value:   item.Value,
+		Version: item.Version,
+		Flags:   item.Flags,
+	}, nil
+}
+
+func (s *Server) Stat(ctx context.Context, req *pb.StatRequest) (*pb.StatResult, error) {
+	meta, err := s.stat(req.GetKey())
+	if isNotFound(err) {
+		return &pb.StatResult{}, nil
+	}
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to stat key")
+	}
+
+	res := &pb.StatResult{
+		Exists:  true,
+		Size:    meta.Size,
+		Version: meta.Version,
+		Flags:   meta.Flags,
+	}
+	if meta.TTL > 0 {
+		res.Ttl = durationpb.New(meta.TTL)
+	}
+	if !meta.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(meta.CreatedAt)
+	}
+	if !meta.ModifiedAt.IsZero() {
+		res.ModifiedAt = timestamppb.New(meta.ModifiedAt)
+	}
+
+	return res, nil
+}
+
+// stat falls back to reading the value, which is not sent to the client anyway.
+func (s *Server) stat(key string) (storage.Meta, error) {
+	if s.stater != nil {
+		return s.stater.Stat(key)
+	}
+
+	item, err := s.storage.Get(key)
+	if err != nil {
+		return storage.Meta{}, err
+	}
+
+	return storage.Meta{
+		Size:    uint64(len(item.Value)),
+		Version: item.Version,
+		Flags:   item.Flags,
 	}, nil
 }
 
+func (s *Server) GetAndTouch(ctx context.Context, req *pb.GetAndTouchRequest) (*pb.GetAndTouchResult, error) {
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.storage.GetAndTouch(req.GetKey(), ttl)
+	s.calls.read(err)
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to get and touch key")
+	}
+
+	return &pb.GetAndTouchResult{
+		Value:   item.Value,
+		Version: item.Version,
+		Flags:   item.Flags,
+	}, nil
+}
+
+func (s *Server) Touch(ctx context.Context, req *pb.TouchRequest) (*pb.TouchResult, error) {
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Touch(req.GetKey(), ttl)
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to touch key")
+	}
+
+	return &pb.TouchResult{}, nil
+}
+
+func (s *Server) MultiGet(ctx context.Context, req *pb.MultiGetRequest) (*pb.MultiGetResult, error) {
+	items, err := s.storage.MultiGet(req.GetKeys())
+	if err != nil {
+		return nil, s.storageError(errCode(err), "", err, "failed to get keys")
+	}
+
+	res := &pb.MultiGetResult{
+		Found: make(map[string]*pb.GetResult, len(items)),
+	}
+	for _, key := range req.GetKeys() {
+		item, ok := items[key]
+		if !ok {
+			res.Missing = append(res.Missing, key)
+			continue
+		}
+
+		res.Found[key] = &pb.GetResult{
+			Value:   item.Value,
+			Version: item.Version,
+			Flags:   item.Flags,
+		}
+	}
+	s.calls.reads(len(req.GetKeys())-len(res.Missing), len(res.Missing))
+
+	return res, nil
+}
+
+func (s *Server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResult, error) {
+	if s.lister == nil {
+		return nil, s.unsupported("storage does not support listing keys")
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize == 0:
+		pageSize = defaultListPageSize
+	case pageSize > maxListPageSize:
+		pageSize = maxListPageSize
+	}
+
+	after, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgument("page_token", "invalid page token")
+	}
+
+	items, err := s.lister.List(req.GetPrefix(), string(after), pageSize+1, req.GetIncludeValues()) // one more to know if there is a next page.
+	if err != nil {
+		return nil, s.storageError(errCode(err), "", err, "failed to list keys")
+	}
+
+	res := &pb.ListResult{}
+	if len(items) > pageSize {
+		items = items[:pageSize]
+		res.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(items[len(items)-1].Key))
+	}
+
+	res.Items = make([]*pb.ListResult_Item, 0, len(items))
+	for _, item := range items {
+		if strings.HasPrefix(item.Key, reservedPrefix) {
+			continue
+		}
+
+		res.Items = append(res.Items, &pb.ListResult_Item{
+			Key:     item.Key,
+			Value:   item.Value,
+			Version: item.Version,
+			Flags:   item.Flags,
+		})
+	}
+
+	return res, nil
+}
+
 func (s *Server) Set(ctx context.Context, req *pb.SetRequest) (*pb.SetResult, error) {
-	err := s.storage.Set(req.GetKey(), req.GetValue())
+	ttl, err := parseTTL(req.GetTtl())
 	if err != nil {
-		return nil, status.Errorf(errCode(err), "failed to set key: %s", err.Error())
+		return nil, err
+	}
+
+	err = s.checkLease(req.GetLease(), req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	if s.leases != nil {
+		defer s.leases.LockKey(req.GetKey())()
 	}
 
+	switch req.GetMode() {
+	case pb.SetMode_SET_MODE_SET:
+		err = s.storage.Set(req.GetKey(), req.GetValue(), req.GetFlags(), ttl)
+		if err != nil {
+			return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to set key")
+		}
+	case pb.SetMode_SET_MODE_ADD:
+		err = s.storage.Add(req.GetKey(), req.GetValue(), req.GetFlags(), ttl)
+		if err != nil {
+			return nil, s.storageError(notStoredCode(err, codes.AlreadyExists), req.GetKey(), err, "failed to add key")
+		}
+	case pb.SetMode_SET_MODE_REPLACE:
+		err = s.storage.Replace(req.GetKey(), req.GetValue(), req.GetFlags(), ttl)
+		if err != nil {
+			return nil, s.storageError(notStoredCode(err, codes.NotFound), req.GetKey(), err, "failed to replace key")
+		}
+	default:
+		return nil, invalidArgument("mode", fmt.Sprintf("unknown set mode: %v", req.GetMode()))
+	}
+
+	s.attach(req.GetLease(), req.GetKey())
+
 	return &pb.SetResult{}, nil
 }
 
 func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResult, error) {
 	err := s.storage.Delete(req.GetKey())
 	if err != nil {
-		return nil, status.Errorf(errCode(err), "failed to delete key: %s", err.Error())
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to delete key")
 	}
 
+	s.detach(req.GetKey())
+
 	return &pb.DeleteResult{}, nil
 }
 
+func (s *Server) Append(ctx context.Context, req *pb.AppendRequest) (*pb.AppendResult, error) {
+	err := s.storage.Append(req.GetKey(), req.GetValue())
+	if err != nil {
+		return nil, s.storageError(notStoredCode(err, codes.NotFound), req.GetKey(), err, "failed to append to key")
+	}
+
+	return &pb.AppendResult{}, nil
+}
+
+func (s *Server) Prepend(ctx context.Context, req *pb.PrependRequest) (*pb.PrependResult, error) {
+	err := s.storage.Prepend(req.GetKey(), req.GetValue())
+	if err != nil {
+		return nil, s.storageError(notStoredCode(err, codes.NotFound), req.GetKey(), err, "failed to prepend to key")
+	}
+
+	return &pb.PrependResult{}, nil
+}
+
+func (s *Server) CompareAndSwap(ctx context.Context, req *pb.CompareAndSwapRequest) (*pb.CompareAndSwapResult, error) {
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetVersion() == 0 {
+		return nil, invalidArgument("version", "version must be set")
+	}
+
+	err = s.storage.CompareAndSwap(req.GetKey(), req.GetValue(), req.GetFlags(), req.GetVersion(), ttl)
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to compare and swap key")
+	}
+
+	return &pb.CompareAndSwapResult{}, nil
+}
+
+func (s *Server) Increment(ctx context.Context, req *pb.IncrementRequest) (*pb.IncrementResult, error) {
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.storage.Increment(req.GetKey(), req.GetDelta(), req.InitialValue, ttl)
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to increment key")
+	}
+
+	return &pb.IncrementResult{
+		Value: v,
+	}, nil
+}
+
+func (s *Server) Decrement(ctx context.Context, req *pb.DecrementRequest) (*pb.DecrementResult, error) {
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.storage.Decrement(req.GetKey(), req.GetDelta(), req.InitialValue, ttl)
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to decrement key")
+	}
+
+	return &pb.DecrementResult{
+		Value: v,
+	}, nil
+}
+
+func (s *Server) MultiSet(ctx context.Context, req *pb.MultiSetRequest) (*pb.MultiSetResult, error) {
+	items := req.GetItems()
+	statuses := make([]*pb.ItemStatus, len(items))
+
+	entries := make([]storage.Entry, 0, len(items))
+	positions := make([]int, 0, len(items)) // positions[i] is the index of entries[i] in items.
+	for i, item := range items {
+		ttl, err := parseTTL(item.GetTtl())
+		if err != nil {
+			statuses[i] = itemStatus(item.GetKey(), err)
+			continue
+		}
+
+		entries = append(entries, storage.Entry{
+			Key:   item.GetKey(),
+			Value: item.GetValue(),
+			Flags: item.GetFlags(),
+			TTL:   ttl,
+		})
+		positions = append(positions, i)
+	}
+
+	errs, err := s.storage.MultiSet(entries)
+	if err != nil {
+		return nil, s.storageError(errCode(err), "", err, "failed to set keys")
+	}
+
+	for i, err := range errs {
+		statuses[positions[i]] = itemStatus(entries[i].Key, err)
+	}
+
+	return &pb.MultiSetResult{
+		Statuses: statuses,
+	}, nil
+}
+
+func (s *Server) MultiDelete(ctx context.Context, req *pb.MultiDeleteRequest) (*pb.MultiDeleteResult, error) {
+	errs, err := s.storage.MultiDelete(req.GetKeys())
+	if err != nil {
+		return nil, s.storageError(errCode(err), "", err, "failed to delete keys")
+	}
+
+	statuses := make([]*pb.ItemStatus, len(errs))
+	for i, err := range errs {
+		if err == nil {
+			s.detach(req.GetKeys()[i])
+		}
+		statuses[i] = itemStatus(req.GetKeys()[i], err)
+	}
+
+	return &pb.MultiDeleteResult{
+		Statuses: statuses,
+	}, nil
+}
+
+// itemStatus reports the outcome of a single item of a batch.
+func itemStatus(key string, err error) *pb.ItemStatus {
+	if err == nil {
+		return &pb.ItemStatus{Key: key}
+	}
+
+	if s, ok := status.FromError(err); ok {
+		return &pb.ItemStatus{
+			Key:     key,
+			Code:    uint32(s.Code()),
+			Message: s.Message(),
+		}
+	}
+
+	return &pb.ItemStatus{
+		Key:     key,
+		Code:    uint32(errCode(err)),
+		Message: err.Error(),
+	}
+}
+
+func parseTTL(ttl *durationpb.Duration) (time.Duration, error) {
+	d := ttl.AsDuration()
+	if d < 0 {
+		return 0, invalidArgument("ttl", "ttl must not be negative")
+	}
+
+	return d, nil
+}
+
+func isNotFound(err error) bool {
+	return implements[interface{ NotFoundErrorMarker() }](err)
+}
+
 func errCode(err error) codes.Code {
 	switch {
 	case implements[interface{ NotFoundErrorMarker() }](err):
 		return codes.NotFound
+	case implements[interface{ NotStoredErrorMarker() }](err):
+		return codes.FailedPrecondition
+	case implements[interface{ ConflictErrorMarker() }](err):
+		return codes.Aborted
+	case implements[interface{ NonNumericErrorMarker() }](err):
+		return codes.FailedPrecondition
+	case implements[interface{ TooLargeErrorMarker() }](err):
+		return codes.InvalidArgument
+	case implements[interface{ InvalidKeyErrorMarker() }](err):
+		return codes.InvalidArgument
+	case implements[interface{ ChunkedErrorMarker() }](err):
+		return codes.FailedPrecondition
+	case implements[interface{ UnavailableErrorMarker() }](err):
+		return codes.Unavailable
+	case implements[interface{ TimeoutErrorMarker() }](err):
+		return codes.DeadlineExceeded
 	case implements[interface{ UnknownErrorMarker() }](err):
 		return codes.Unknown
 	}
@@ -50,6 +425,47 @@ func errCode(err error) codes.Code {
 	return codes.Internal
 }
 
+// errReason works like errCode. Reason of "not stored" errors is derived from code, see notStoredCode.
+func errReason(code codes.Code, err error) pb.ErrorReason {
+	switch {
+	case implements[interface{ NotFoundErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_KEY_NOT_FOUND
+	case implements[interface{ NotStoredErrorMarker() }](err):
+		switch code { //nolint:exhaustive
+		case codes.AlreadyExists:
+			return pb.ErrorReason_ERROR_REASON_KEY_EXISTS
+		case codes.NotFound:
+			return pb.ErrorReason_ERROR_REASON_KEY_NOT_FOUND
+		}
+		return pb.ErrorReason_ERROR_REASON_NOT_STORED
+	case implements[interface{ ConflictErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_VERSION_MISMATCH
+	case implements[interface{ NonNumericErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_NON_NUMERIC_VALUE
+	case implements[interface{ TooLargeErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_VALUE_TOO_LARGE
+	case implements[interface{ InvalidKeyErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_INVALID_KEY
+	case implements[interface{ ChunkedErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_CHUNKED_VALUE
+	case implements[interface{ UnavailableErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_STORAGE_UNAVAILABLE
+	case implements[interface{ TimeoutErrorMarker() }](err):
+		return pb.ErrorReason_ERROR_REASON_STORAGE_TIMEOUT
+	}
+
+	return pb.ErrorReason_ERROR_REASON_STORAGE_FAILURE
+}
+
+// notStoredCode works like errCode but maps "not stored" errors to code, because their meaning depends on the command.
+func notStoredCode(err error, code codes.Code) codes.Code {
+	if implements[interface{ NotStoredErrorMarker() }](err) {
+		return code
+	}
+
+	return errCode(err)
+}
+
 func implements[T any](err error) bool {
 	for err != nil {
 		if _, ok := err.(T); ok {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
@@ -12,9 +13,12 @@ import (
 	"go.uber.org/goleak"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/IlyaFloppy/grpcstore/internal/config"
 	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
 	"github.com/IlyaFloppy/grpcstore/public-api/pb"
 )
 
@@ -23,21 +27,23 @@ func TestGet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	storage := servermocks.NewMockIStorage(ctrl)
-	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
 
 	t.Run("happy case", func(t *testing.T) {
-		storage.EXPECT().Get("key").Return([]byte("12345"), nil)
+		storage.EXPECT().Get("key").Return(storagepkg.Item{Value: []byte("12345"), Version: 42, Flags: 7}, nil)
 		res, err := server.Get(context.Background(), &pb.GetRequest{
 			Key: "key",
 		})
 		require.NoError(t, err)
 		require.Equal(t, &pb.GetResult{
-			Value: []byte("12345"),
+			Value:   []byte("12345"),
+			Version: 42,
+			Flags:   7,
 		}, res)
 	})
 
 	t.Run("internal error", func(t *testing.T) {
-		storage.EXPECT().Get("key").Return(nil, errors.New("failed on purpose"))
+		storage.EXPECT().Get("key").Return(storagepkg.Item{}, errors.New("failed on purpose"))
 		res, err := server.Get(context.Background(), &pb.GetRequest{
 			Key: "key",
 		})
@@ -47,25 +53,287 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestStat(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	stater := servermocks.NewMockIStater(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, struct {
+		IStorage
+		IStater
+	}{storage, stater}, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		stater.EXPECT().Stat("key").Return(storagepkg.Meta{
+			Size:       5,
+			Version:    42,
+			Flags:      7,
+			TTL:        time.Minute,
+			CreatedAt:  created,
+			ModifiedAt: created.Add(time.Hour),
+		}, nil)
+		res, err := server.Stat(context.Background(), &pb.StatRequest{Key: "key"})
+		require.NoError(t, err)
+		require.Equal(t, &pb.StatResult{
+			Exists:     true,
+			Size:       5,
+			Version:    42,
+			Flags:      7,
+			Ttl:        durationpb.New(time.Minute),
+			CreatedAt:  timestamppb.New(created),
+			ModifiedAt: timestamppb.New(created.Add(time.Hour)),
+		}, res)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		stater.EXPECT().Stat("key").Return(storagepkg.Meta{}, storagepkg.ErrNotFound)
+		res, err := server.Stat(context.Background(), &pb.StatRequest{Key: "key"})
+		require.NoError(t, err)
+		require.Equal(t, &pb.StatResult{}, res)
+	})
+
+	t.Run("fallback to get", func(t *testing.T) {
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+		storage.EXPECT().Get("key").Return(storagepkg.Item{Value: []byte("12345"), Version: 42}, nil)
+		res, err := server.Stat(context.Background(), &pb.StatRequest{Key: "key"})
+		require.NoError(t, err)
+		require.Equal(t, &pb.StatResult{Exists: true, Size: 5, Version: 42}, res)
+	})
+}
+
+func TestTouch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("touch", func(t *testing.T) {
+		storage.EXPECT().Touch("key", time.Minute).Return(nil)
+		res, err := server.Touch(context.Background(), &pb.TouchRequest{
+			Key: "key",
+			Ttl: durationpb.New(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.TouchResult{}, res)
+	})
+
+	t.Run("get and touch", func(t *testing.T) {
+		storage.EXPECT().GetAndTouch("key", time.Minute).Return(storagepkg.Item{Value: []byte("12345"), Version: 42}, nil)
+		res, err := server.GetAndTouch(context.Background(), &pb.GetAndTouchRequest{
+			Key: "key",
+			Ttl: durationpb.New(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.GetAndTouchResult{
+			Value:   []byte("12345"),
+			Version: 42,
+		}, res)
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		res, err := server.Touch(context.Background(), &pb.TouchRequest{
+			Key: "key",
+			Ttl: durationpb.New(-time.Minute),
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestMultiGet(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		storage.EXPECT().MultiGet([]string{"a", "b", "c"}).Return(map[string]storagepkg.Item{
+			"a": {Value: []byte("1"), Version: 1},
+			"c": {Value: []byte("3"), Version: 3},
+		}, nil)
+		res, err := server.MultiGet(context.Background(), &pb.MultiGetRequest{
+			Keys: []string{"a", "b", "c"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.MultiGetResult{
+			Found: map[string]*pb.GetResult{
+				"a": {Value: []byte("1"), Version: 1},
+				"c": {Value: []byte("3"), Version: 3},
+			},
+			Missing: []string{"b"},
+		}, res)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		storage.EXPECT().MultiGet([]string{"a"}).Return(nil, errors.New("failed on purpose"))
+		res, err := server.MultiGet(context.Background(), &pb.MultiGetRequest{
+			Keys: []string{"a"},
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestList(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	lister := servermocks.NewMockILister(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, struct {
+		IStorage
+		ILister
+	}{storage, lister}, nil)
+
+	t.Run("pagination", func(t *testing.T) {
+		lister.EXPECT().List("user/", "", 3, true).Return([]storagepkg.KeyItem{
+			{Key: "user/1", Item: storagepkg.Item{Value: []byte("1"), Version: 1}},
+			{Key: "user/2", Item: storagepkg.Item{Value: []byte("2"), Version: 2}},
+			{Key: "user/3", Item: storagepkg.Item{Value: []byte("3"), Version: 3}},
+		}, nil)
+		res, err := server.List(context.Background(), &pb.ListRequest{
+			Prefix:        "user/",
+			PageSize:      2,
+			IncludeValues: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.GetItems(), 2)
+		require.Equal(t, "user/2", res.GetItems()[1].GetKey())
+		require.Equal(t, []byte("2"), res.GetItems()[1].GetValue())
+		require.NotEmpty(t, res.GetNextPageToken())
+
+		lister.EXPECT().List("user/", "user/2", 3, true).Return([]storagepkg.KeyItem{
+			{Key: "user/3", Item: storagepkg.Item{Value: []byte("3"), Version: 3}},
+		}, nil)
+		res, err = server.List(context.Background(), &pb.ListRequest{
+			Prefix:        "user/",
+			PageSize:      2,
+			PageToken:     res.GetNextPageToken(),
+			IncludeValues: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.GetItems(), 1)
+		require.Empty(t, res.GetNextPageToken())
+	})
+
+	t.Run("reserved keys", func(t *testing.T) {
+		lister.EXPECT().List("", "", 3, false).Return([]storagepkg.KeyItem{
+			{Key: fenceKey("lock")},
+			{Key: fenceKey("lock2")},
+			{Key: "lock"},
+		}, nil)
+		res, err := server.List(context.Background(), &pb.ListRequest{PageSize: 2})
+		require.NoError(t, err)
+		require.Empty(t, res.GetItems())
+		require.NotEmpty(t, res.GetNextPageToken())
+
+		lister.EXPECT().List("", fenceKey("lock2"), 3, false).Return([]storagepkg.KeyItem{{Key: "lock"}}, nil)
+		res, err = server.List(context.Background(), &pb.ListRequest{PageSize: 2, PageToken: res.GetNextPageToken()})
+		require.NoError(t, err)
+		require.Len(t, res.GetItems(), 1)
+		require.Equal(t, "lock", res.GetItems()[0].GetKey())
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		res, err := server.List(context.Background(), &pb.ListRequest{
+			PageToken: "%%%",
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unsupported storage", func(t *testing.T) {
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+		res, err := server.List(context.Background(), &pb.ListRequest{})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
 func TestSet(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctrl := gomock.NewController(t)
 
 	storage := servermocks.NewMockIStorage(ctrl)
-	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
 
 	t.Run("happy case", func(t *testing.T) {
-		storage.EXPECT().Set("key", []byte("12345")).Return(nil)
+		storage.EXPECT().Set("key", []byte("12345"), uint32(0), time.Duration(0)).Return(nil)
+		res, err := server.Set(context.Background(), &pb.SetRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.SetResult{}, res)
+	})
+
+	t.Run("with ttl", func(t *testing.T) {
+		storage.EXPECT().Set("key", []byte("12345"), uint32(0), time.Minute).Return(nil)
+		res, err := server.Set(context.Background(), &pb.SetRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+			Ttl:   durationpb.New(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.SetResult{}, res)
+	})
+
+	t.Run("with flags", func(t *testing.T) {
+		storage.EXPECT().Set("key", []byte("12345"), uint32(7), time.Duration(0)).Return(nil)
 		res, err := server.Set(context.Background(), &pb.SetRequest{
 			Key:   "key",
 			Value: []byte("12345"),
+			Flags: 7,
 		})
 		require.NoError(t, err)
 		require.Equal(t, &pb.SetResult{}, res)
 	})
 
+	t.Run("add existing key", func(t *testing.T) {
+		storage.EXPECT().Add("key", []byte("12345"), uint32(0), time.Duration(0)).Return(storagepkg.ErrNotStored)
+		res, err := server.Set(context.Background(), &pb.SetRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+			Mode:  pb.SetMode_SET_MODE_ADD,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("replace missing key", func(t *testing.T) {
+		storage.EXPECT().Replace("key", []byte("12345"), uint32(0), time.Duration(0)).Return(storagepkg.ErrNotStored)
+		res, err := server.Set(context.Background(), &pb.SetRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+			Mode:  pb.SetMode_SET_MODE_REPLACE,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		res, err := server.Set(context.Background(), &pb.SetRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+			Ttl:   durationpb.New(-time.Minute),
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
 	t.Run("internal error", func(t *testing.T) {
-		storage.EXPECT().Set("key", []byte("12345")).Return(errors.New("failed on purpose"))
+		storage.EXPECT().Set("key", []byte("12345"), uint32(0), time.Duration(0)).Return(errors.New("failed on purpose"))
 		res, err := server.Set(context.Background(), &pb.SetRequest{
 			Key:   "key",
 			Value: []byte("12345"),
@@ -81,7 +349,7 @@ func TestDelete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	storage := servermocks.NewMockIStorage(ctrl)
-	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
 
 	t.Run("happy case", func(t *testing.T) {
 		storage.EXPECT().Delete("key").Return(nil)
@@ -102,3 +370,198 @@ func TestDelete(t *testing.T) {
 		require.Equal(t, codes.Internal, status.Code(err))
 	})
 }
+
+func TestAppend(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		storage.EXPECT().Append("key", []byte("12345")).Return(nil)
+		res, err := server.Append(context.Background(), &pb.AppendRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.AppendResult{}, res)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		storage.EXPECT().Append("key", []byte("12345")).Return(storagepkg.ErrNotStored)
+		res, err := server.Append(context.Background(), &pb.AppendRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestPrepend(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	storage.EXPECT().Prepend("key", []byte("12345")).Return(storagepkg.ErrNotStored)
+	res, err := server.Prepend(context.Background(), &pb.PrependRequest{
+		Key:   "key",
+		Value: []byte("12345"),
+	})
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		storage.EXPECT().CompareAndSwap("key", []byte("12345"), uint32(0), uint64(42), time.Duration(0)).Return(nil)
+		res, err := server.CompareAndSwap(context.Background(), &pb.CompareAndSwapRequest{
+			Key:     "key",
+			Value:   []byte("12345"),
+			Version: 42,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.CompareAndSwapResult{}, res)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		storage.EXPECT().CompareAndSwap("key", []byte("12345"), uint32(0), uint64(42), time.Duration(0)).Return(storagepkg.ErrConflict)
+		res, err := server.CompareAndSwap(context.Background(), &pb.CompareAndSwapRequest{
+			Key:     "key",
+			Value:   []byte("12345"),
+			Version: 42,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		res, err := server.CompareAndSwap(context.Background(), &pb.CompareAndSwapRequest{
+			Key:   "key",
+			Value: []byte("12345"),
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestIncrement(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		initial := uint64(10)
+		storage.EXPECT().Increment("key", uint64(5), &initial, time.Minute).Return(uint64(15), nil)
+		res, err := server.Increment(context.Background(), &pb.IncrementRequest{
+			Key:          "key",
+			Delta:        5,
+			InitialValue: &initial,
+			Ttl:          durationpb.New(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, &pb.IncrementResult{Value: 15}, res)
+	})
+
+	t.Run("non-numeric value", func(t *testing.T) {
+		storage.EXPECT().Increment("key", uint64(5), nil, time.Duration(0)).Return(uint64(0), storagepkg.ErrNonNumeric)
+		res, err := server.Increment(context.Background(), &pb.IncrementRequest{
+			Key:   "key",
+			Delta: 5,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestDecrement(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	storage.EXPECT().Decrement("key", uint64(5), nil, time.Duration(0)).Return(uint64(0), nil)
+	res, err := server.Decrement(context.Background(), &pb.DecrementRequest{
+		Key:   "key",
+		Delta: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &pb.DecrementResult{Value: 0}, res)
+}
+
+func TestMultiSet(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("per item statuses", func(t *testing.T) {
+		storage.EXPECT().MultiSet([]storagepkg.Entry{
+			{Key: "a", Value: []byte("1"), TTL: time.Minute},
+			{Key: "c", Value: []byte("3")},
+		}).Return([]error{nil, storagepkg.ErrNotStored}, nil)
+		res, err := server.MultiSet(context.Background(), &pb.MultiSetRequest{
+			Items: []*pb.MultiSetRequest_Item{
+				{Key: "a", Value: []byte("1"), Ttl: durationpb.New(time.Minute)},
+				{Key: "b", Value: []byte("2"), Ttl: durationpb.New(-time.Minute)},
+				{Key: "c", Value: []byte("3")},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.GetStatuses(), 3)
+		require.Equal(t, "a", res.GetStatuses()[0].GetKey())
+		require.Equal(t, uint32(codes.OK), res.GetStatuses()[0].GetCode())
+		require.Equal(t, "b", res.GetStatuses()[1].GetKey())
+		require.Equal(t, uint32(codes.InvalidArgument), res.GetStatuses()[1].GetCode())
+		require.Equal(t, "c", res.GetStatuses()[2].GetKey())
+		require.Equal(t, uint32(codes.FailedPrecondition), res.GetStatuses()[2].GetCode())
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		storage.EXPECT().MultiSet(gomock.Any()).Return(nil, errors.New("failed on purpose"))
+		res, err := server.MultiSet(context.Background(), &pb.MultiSetRequest{
+			Items: []*pb.MultiSetRequest_Item{{Key: "a"}},
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestMultiDelete(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	storage.EXPECT().MultiDelete([]string{"a", "b"}).Return([]error{nil, errors.New("failed on purpose")}, nil)
+	res, err := server.MultiDelete(context.Background(), &pb.MultiDeleteRequest{
+		Keys: []string{"a", "b"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &pb.MultiDeleteResult{
+		Statuses: []*pb.ItemStatus{
+			{Key: "a"},
+			{Key: "b", Code: uint32(codes.Internal), Message: "failed on purpose"},
+		},
+	}, res)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+// errorDomain is the ErrorInfo domain of errors returned by the server.
+const errorDomain = "grpcstore"
+
+// retryDelays holds RetryInfo delays of codes that are worth retrying.
+var retryDelays = map[codes.Code]time.Duration{
+	codes.Unavailable:       time.Second,
+	codes.ResourceExhausted: time.Second,
+	codes.Aborted:           0,
+}
+
+// newError builds an error with ErrorInfo describing key and storage backend.
+func (s *Server) newError(code codes.Code, reason pb.ErrorReason, key, msg string) error {
+	return detailedError(code, msg, errorInfo(reason, key, s.backend))
+}
+
+// storageError describes a failed storage call.
+func (s *Server) storageError(code codes.Code, key string, err error, msg string) error {
+	return s.newError(code, errReason(code, err), key, fmt.Sprintf("%s: %s", msg, err.Error()))
+}
+
+// leaseError describes a failed lease manager call.
+func (s *Server) leaseError(key string, err error, msg string) error {
+	code := errCode(err)
+	if code == codes.NotFound {
+		return s.newError(code, pb.ErrorReason_ERROR_REASON_LEASE_NOT_FOUND, key, fmt.Sprintf("%s: %s", msg, err.Error()))
+	}
+
+	return s.storageError(code, key, err, msg)
+}
+
+// unsupported reports a call that storage or server configuration does not support.
+func (s *Server) unsupported(msg string) error {
+	return s.newError(codes.Unimplemented, pb.ErrorReason_ERROR_REASON_UNSUPPORTED, "", msg)
+}
+
+// invalidArgument reports a malformed request. Field is the path of the offending request field, if there is one.
+func invalidArgument(field, msg string) error {
+	details := []protoiface.MessageV1{errorInfo(pb.ErrorReason_ERROR_REASON_INVALID_ARGUMENT, "", "")}
+	if field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: msg}},
+		})
+	}
+
+	return detailedError(codes.InvalidArgument, msg, details...)
+}
+
+func errorInfo(reason pb.ErrorReason, key, backend string) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{
+		Reason: reason.String(),
+		Domain: errorDomain,
+	}
+
+	if key != "" || backend != "" {
+		info.Metadata = make(map[string]string, 2)
+	}
+	if key != "" {
+		info.Metadata["key"] = key
+	}
+	if backend != "" {
+		info.Metadata["backend"] = backend
+	}
+
+	return info
+}
+
+// detailedError attaches details and RetryInfo if code is worth retrying. Details are dropped if they can not be
+// attached, the code and message are still returned then.
+func detailedError(code codes.Code, msg string, details ...protoiface.MessageV1) error {
+	if delay, ok := retryDelays[code]; ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	}
+
+	st := status.New(code, msg)
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+type namedStorage struct {
+	IStorage
+}
+
+func (namedStorage) Name() string {
+	return "test-storage"
+}
+
+func TestErrorDetails(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, namedStorage{storage}, nil)
+
+	t.Run("storage error", func(t *testing.T) {
+		storage.EXPECT().Add("key", []byte("12345"), uint32(0), time.Duration(0)).Return(storagepkg.ErrNotStored)
+		_, err := server.Set(context.Background(), &pb.SetRequest{Key: "key", Value: []byte("12345"), Mode: pb.SetMode_SET_MODE_ADD})
+		st := status.Convert(err)
+		require.Equal(t, codes.AlreadyExists, st.Code())
+		require.Equal(t, "failed to add key: not stored", st.Message())
+		requireDetails(t, st, &errdetails.ErrorInfo{
+			Reason:   pb.ErrorReason_ERROR_REASON_KEY_EXISTS.String(),
+			Domain:   errorDomain,
+			Metadata: map[string]string{"key": "key", "backend": "test-storage"},
+		})
+	})
+
+	t.Run("retryable error", func(t *testing.T) {
+		storage.EXPECT().CompareAndSwap("key", []byte("12345"), uint32(0), uint64(42), time.Duration(0)).Return(storagepkg.ErrConflict)
+		_, err := server.CompareAndSwap(context.Background(), &pb.CompareAndSwapRequest{Key: "key", Value: []byte("12345"), Version: 42})
+		st := status.Convert(err)
+		require.Equal(t, codes.Aborted, st.Code())
+		requireDetails(t, st, &errdetails.ErrorInfo{
+			Reason:   pb.ErrorReason_ERROR_REASON_VERSION_MISMATCH.String(),
+			Domain:   errorDomain,
+			Metadata: map[string]string{"key": "key", "backend": "test-storage"},
+		}, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(0),
+		})
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+		_, err := server.Touch(context.Background(), &pb.TouchRequest{Key: "key", Ttl: durationpb.New(-time.Second)})
+		st := status.Convert(err)
+		require.Equal(t, codes.InvalidArgument, st.Code())
+		requireDetails(t, st, &errdetails.ErrorInfo{
+			Reason: pb.ErrorReason_ERROR_REASON_INVALID_ARGUMENT.String(),
+			Domain: errorDomain,
+		}, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "ttl", Description: "ttl must not be negative"}},
+		})
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := server.List(context.Background(), &pb.ListRequest{})
+		st := status.Convert(err)
+		require.Equal(t, codes.Unimplemented, st.Code())
+		requireDetails(t, st, &errdetails.ErrorInfo{
+			Reason:   pb.ErrorReason_ERROR_REASON_UNSUPPORTED.String(),
+			Domain:   errorDomain,
+			Metadata: map[string]string{"backend": "test-storage"},
+		})
+	})
+}
+
+func TestErrCode(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		code   codes.Code
+		reason pb.ErrorReason
+	}{
+		{storagepkg.ErrNotFound, codes.NotFound, pb.ErrorReason_ERROR_REASON_KEY_NOT_FOUND},
+		{storagepkg.ErrNotStored, codes.FailedPrecondition, pb.ErrorReason_ERROR_REASON_NOT_STORED},
+		{storagepkg.ErrConflict, codes.Aborted, pb.ErrorReason_ERROR_REASON_VERSION_MISMATCH},
+		{storagepkg.ErrNonNumeric, codes.FailedPrecondition, pb.ErrorReason_ERROR_REASON_NON_NUMERIC_VALUE},
+		{storagepkg.ErrTooLarge, codes.InvalidArgument, pb.ErrorReason_ERROR_REASON_VALUE_TOO_LARGE},
+		{storagepkg.ErrInvalidKey, codes.InvalidArgument, pb.ErrorReason_ERROR_REASON_INVALID_KEY},
+		{storagepkg.ErrUnavailable, codes.Unavailable, pb.ErrorReason_ERROR_REASON_STORAGE_UNAVAILABLE},
+		{storagepkg.ErrTimeout, codes.DeadlineExceeded, pb.ErrorReason_ERROR_REASON_STORAGE_TIMEOUT},
+		{storagepkg.ErrChunked, codes.FailedPrecondition, pb.ErrorReason_ERROR_REASON_CHUNKED_VALUE},
+		{memcached.ErrNotFound, codes.NotFound, pb.ErrorReason_ERROR_REASON_KEY_NOT_FOUND},
+		{memcached.ErrTooLarge, codes.InvalidArgument, pb.ErrorReason_ERROR_REASON_VALUE_TOO_LARGE},
+		{memcached.ErrUnknownResponse, codes.Unknown, pb.ErrorReason_ERROR_REASON_STORAGE_FAILURE},
+		{fmt.Errorf("failed to get key: %w", storagepkg.Translate(memcached.ErrCASConflict)), codes.Aborted,
+			pb.ErrorReason_ERROR_REASON_VERSION_MISMATCH},
+		{errors.New("failed on purpose"), codes.Internal, pb.ErrorReason_ERROR_REASON_STORAGE_FAILURE},
+	} {
+		require.Equal(t, tc.code, errCode(tc.err), tc.err.Error())
+		require.Equal(t, tc.reason, errReason(tc.code, tc.err), tc.err.Error())
+	}
+}
+
+func requireDetails(t *testing.T, st *status.Status, expected ...proto.Message) {
+	t.Helper()
+
+	details := st.Details()
+	require.Len(t, details, len(expected))
+	for i, d := range details {
+		m, ok := d.(proto.Message)
+		require.True(t, ok, "detail %d is %v", i, d)
+		require.True(t, proto.Equal(expected[i], m), "detail %d: expected %v, got %v", i, expected[i], m)
+	}
+}
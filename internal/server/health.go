@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+
+	serviceName = "pb.GRPCStoreService" // name of the service in health checks.
+)
+
+// checkHealth reports the server as serving once storage is ready and for as long as storage passes health checks.
+func (s *Server) checkHealth(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-s.storageReady:
+	}
+
+	ticker := time.NewTicker(s.healthCheckInterval)
+	defer ticker.Stop()
+
+	serving, checked := false, false // status changes are logged, not every check.
+	for {
+		err := s.ping()
+		switch {
+		case err != nil && (serving || !checked):
+			s.logger.Warn().Err(err).Msg("storage health check failed")
+		case err == nil && !serving:
+			s.logger.Info().Msg("storage is healthy")
+		}
+
+		serving, checked = err == nil, true
+		s.setServing(serving)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) ping() error {
+	if s.healthChecker == nil {
+		return nil
+	}
+
+	return s.healthChecker.Ping()
+}
+
+// setServing sets status of the whole server and of the store service, they are the same as it is the only service.
+func (s *Server) setServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(serviceName, status)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+)
+
+type readyStorage struct {
+	IStorage
+	IHealthChecker
+	readyCh chan struct{}
+}
+
+func (s readyStorage) ReadyCh() <-chan struct{} {
+	return s.readyCh
+}
+
+func TestHealth(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	checker := servermocks.NewMockIHealthChecker(ctrl)
+	readyCh := make(chan struct{})
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{
+		Address:             "127.0.0.1:0",
+		HealthCheckInterval: time.Millisecond,
+	}, readyStorage{storage, checker, readyCh}, nil)
+
+	status := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		res, err := server.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return res.GetStatus()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx)
+	}()
+	<-server.ReadyCh()
+
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(serviceName))
+
+	var failing int32
+	checker.EXPECT().Ping().DoAndReturn(func() error {
+		if atomic.LoadInt32(&failing) == 1 {
+			return errors.New("failed on purpose")
+		}
+		return nil
+	}).AnyTimes()
+	close(readyCh)
+
+	require.Eventually(t, func() bool {
+		return status("") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond)
+
+	atomic.StoreInt32(&failing, 1)
+	require.Eventually(t, func() bool {
+		return status(serviceName) == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, time.Millisecond)
+
+	atomic.StoreInt32(&failing, 0)
+	require.Eventually(t, func() bool {
+		return status(serviceName) == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-errCh)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""))
+}
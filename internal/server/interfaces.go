@@ -1,8 +1,78 @@
 package server
 
-//go:generate mockgen -destination=mocks/interfaces.go . IStorage
+import (
+	"time"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+)
+
+//go:generate mockgen -destination=mocks/interfaces.go . IStorage,ILister,IStreamer,ITransactor,ILeaseManager,IStater,IHealthChecker,IStatsReporter
 type IStorage interface {
-	Get(key string) ([]byte, error)
-	Set(key string, value []byte) error
+	Get(key string) (storage.Item, error)
+	GetAndTouch(key string, ttl time.Duration) (storage.Item, error)
+	Touch(key string, ttl time.Duration) error
+	MultiGet(keys []string) (map[string]storage.Item, error)
+	Set(key string, value []byte, flags uint32, ttl time.Duration) error
+	Add(key string, value []byte, flags uint32, ttl time.Duration) error
+	Replace(key string, value []byte, flags uint32, ttl time.Duration) error
+	Append(key string, value []byte) error
+	Prepend(key string, value []byte) error
+	CompareAndSwap(key string, value []byte, flags uint32, version uint64, ttl time.Duration) error
 	Delete(key string) error
+	Increment(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error)
+	Decrement(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error)
+	MultiSet(entries []storage.Entry) ([]error, error)
+	MultiDelete(keys []string) ([]error, error)
+}
+
+// IExpirationNotifier is implemented by storages that expire keys themselves and can report it to watchers.
+type IExpirationNotifier interface {
+	OnExpire(fn func(key string))
+}
+
+// ILister is implemented by storages that can enumerate their keys in order.
+type ILister interface {
+	List(prefix, after string, limit int, withValues bool) ([]storage.KeyItem, error)
+}
+
+// IStater is implemented by storages that can describe an item without reading its value.
+type IStater interface {
+	Stat(key string) (storage.Meta, error)
+}
+
+// IHealthChecker is implemented by storages that depend on external services and can check that they are reachable.
+type IHealthChecker interface {
+	Ping() error
+}
+
+// IStatsReporter is implemented by storages that report their key count, total size and raw backend stats.
+type IStatsReporter interface {
+	Stats() (storage.Stats, error)
+}
+
+// IConcurrencyLimiter is implemented by storages that can serve a limited number of concurrent operations.
+type IConcurrencyLimiter interface {
+	Concurrency() int
+}
+
+// IStreamer is implemented by storages that keep values too large to be written or read at once.
+type IStreamer interface {
+	PutStream(key string, flags uint32, ttl time.Duration) (storage.ChunkWriter, error)
+	GetStream(key string) (storage.ChunkReader, error)
+}
+
+// ITransactor is implemented by storages that can execute conditional multi-key transactions.
+type ITransactor interface {
+	Txn(txn storage.Txn) (storage.TxnResult, error)
+}
+
+type ILeaseManager interface {
+	Grant(ttl time.Duration) int64
+	Exists(id int64) bool
+	Revoke(id int64) ([]string, error)
+	KeepAlive(id int64) (time.Duration, error)
+	LockKey(key string) (unlock func())
+	Attach(id int64, key string) error
+	Detach(key string)
+	OnExpire(fn func(key string))
 }
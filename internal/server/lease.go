@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+func (s *Server) LeaseGrant(ctx context.Context, req *pb.LeaseGrantRequest) (*pb.LeaseGrantResult, error) {
+	if s.leases == nil {
+		return nil, s.unsupported("leases are disabled")
+	}
+
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		return nil, invalidArgument("ttl", "ttl must be positive")
+	}
+
+	return &pb.LeaseGrantResult{
+		Id:  s.leases.Grant(ttl),
+		Ttl: durationpb.New(ttl),
+	}, nil
+}
+
+func (s *Server) LeaseRevoke(ctx context.Context, req *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResult, error) {
+	if s.leases == nil {
+		return nil, s.unsupported("leases are disabled")
+	}
+
+	keys, err := s.leases.Revoke(req.GetId())
+	if err != nil {
+		return nil, s.leaseError("", err, "failed to revoke lease")
+	}
+
+	for _, key := range keys {
+		s.watchers.deleted(key)
+	}
+
+	return &pb.LeaseRevokeResult{}, nil
+}
+
+func (s *Server) LeaseKeepAlive(stream pb.GRPCStoreService_LeaseKeepAliveServer) error {
+	if s.leases == nil {
+		return s.unsupported("leases are disabled")
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		res := &pb.LeaseKeepAliveResult{
+			Id:  req.GetId(),
+			Ttl: durationpb.New(0),
+		}
+
+		ttl, err := s.leases.KeepAlive(req.GetId())
+		if err == nil {
+			res.Ttl = durationpb.New(ttl)
+		}
+
+		err = stream.Send(res)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// checkLease fails if the lease is unknown or expired, it is called before writing so that such writes have no effect.
+func (s *Server) checkLease(lease int64, key string) error {
+	if lease == 0 {
+		return nil
+	}
+	if s.leases == nil {
+		return s.unsupported("leases are disabled")
+	}
+	if !s.leases.Exists(lease) {
+		return s.newError(codes.NotFound, pb.ErrorReason_ERROR_REASON_LEASE_NOT_FOUND, key, "lease not found")
+	}
+
+	return nil
+}
+
+// attach attaches written key to the lease checked by checkLease or detaches it if lease is zero. If the lease expired
+// in between, the key is deleted as it would have been by the expiration, otherwise it would never expire.
+func (s *Server) attach(lease int64, key string) {
+	if lease == 0 {
+		s.detach(key)
+		return
+	}
+
+	err := s.leases.Attach(lease, key)
+	if err == nil {
+		return
+	}
+
+	if err := s.storage.Delete(key); err != nil {
+		s.logger.Error().Err(err).Str("key", key).Msg("failed to delete key of an expired lease")
+	}
+}
+
+func (s *Server) detach(key string) {
+	if s.leases != nil {
+		s.leases.Detach(key)
+	}
+}
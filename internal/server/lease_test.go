@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/lease"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+type keepAliveStream struct {
+	grpc.ServerStream
+	requests []*pb.LeaseKeepAliveRequest
+	results  []*pb.LeaseKeepAliveResult
+}
+
+func (s *keepAliveStream) Recv() (*pb.LeaseKeepAliveRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *keepAliveStream) Send(res *pb.LeaseKeepAliveResult) error {
+	s.results = append(s.results, res)
+	return nil
+}
+
+func TestLease(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	leases := servermocks.NewMockILeaseManager(ctrl)
+	leases.EXPECT().OnExpire(gomock.Any())
+	leases.EXPECT().LockKey("key").Return(func() {}).AnyTimes()
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, leases)
+
+	t.Run("grant", func(t *testing.T) {
+		leases.EXPECT().Grant(time.Minute).Return(int64(1))
+		res, err := server.LeaseGrant(context.Background(), &pb.LeaseGrantRequest{Ttl: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res.Id)
+		require.Equal(t, time.Minute, res.Ttl.AsDuration())
+
+		_, err = server.LeaseGrant(context.Background(), &pb.LeaseGrantRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("set with lease", func(t *testing.T) {
+		leases.EXPECT().Exists(int64(1)).Return(true)
+		storage.EXPECT().Set("key", []byte("value"), uint32(0), time.Duration(0)).Return(nil)
+		leases.EXPECT().Attach(int64(1), "key").Return(nil)
+		_, err := server.Set(context.Background(), &pb.SetRequest{Key: "key", Value: []byte("value"), Lease: 1})
+		require.NoError(t, err)
+
+		storage.EXPECT().Set("key", []byte("value"), uint32(0), time.Duration(0)).Return(nil)
+		leases.EXPECT().Detach("key")
+		_, err = server.Set(context.Background(), &pb.SetRequest{Key: "key", Value: []byte("value")})
+		require.NoError(t, err)
+	})
+
+	t.Run("set with missing lease", func(t *testing.T) {
+		leases.EXPECT().Exists(int64(2)).Return(false)
+		_, err := server.Set(context.Background(), &pb.SetRequest{Key: "key", Value: []byte("value"), Lease: 2})
+		require.Equal(t, codes.NotFound, status.Code(err)) // storage is not called.
+	})
+
+	t.Run("set with lease expiring during write", func(t *testing.T) {
+		leases.EXPECT().Exists(int64(2)).Return(true)
+		storage.EXPECT().Set("key", []byte("value"), uint32(0), time.Duration(0)).Return(nil)
+		leases.EXPECT().Attach(int64(2), "key").Return(lease.ErrNotFound)
+		storage.EXPECT().Delete("key").Return(nil)
+		_, err := server.Set(context.Background(), &pb.SetRequest{Key: "key", Value: []byte("value"), Lease: 2})
+		require.NoError(t, err)
+	})
+
+	t.Run("delete detaches key", func(t *testing.T) {
+		storage.EXPECT().Delete("key").Return(nil)
+		leases.EXPECT().Detach("key")
+		_, err := server.Delete(context.Background(), &pb.DeleteRequest{Key: "key"})
+		require.NoError(t, err)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		leases.EXPECT().Revoke(int64(1)).Return([]string{"key"}, nil)
+		_, err := server.LeaseRevoke(context.Background(), &pb.LeaseRevokeRequest{Id: 1})
+		require.NoError(t, err)
+
+		leases.EXPECT().Revoke(int64(1)).Return(nil, lease.ErrNotFound)
+		_, err = server.LeaseRevoke(context.Background(), &pb.LeaseRevokeRequest{Id: 1})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("keep alive", func(t *testing.T) {
+		leases.EXPECT().KeepAlive(int64(1)).Return(time.Minute, nil)
+		leases.EXPECT().KeepAlive(int64(2)).Return(time.Duration(0), lease.ErrNotFound)
+		stream := &keepAliveStream{requests: []*pb.LeaseKeepAliveRequest{{Id: 1}, {Id: 2}}}
+		require.NoError(t, server.LeaseKeepAlive(stream))
+		require.Len(t, stream.results, 2)
+		require.Equal(t, time.Minute, stream.results[0].Ttl.AsDuration())
+		require.Equal(t, int64(2), stream.results[1].Id)
+		require.Zero(t, stream.results[1].Ttl.AsDuration())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+		_, err := server.LeaseGrant(context.Background(), &pb.LeaseGrantRequest{Ttl: durationpb.New(time.Minute)})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = server.Set(context.Background(), &pb.SetRequest{Key: "key", Lease: 1})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
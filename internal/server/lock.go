@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+const (
+	lockPollMinInterval = 10 * time.Millisecond
+	lockPollMaxInterval = time.Second
+)
+
+func fenceKey(key string) string {
+	return reservedPrefix + "fence/" + key
+}
+
+func (s *Server) Lock(ctx context.Context, req *pb.LockRequest) (*pb.LockResult, error) {
+	if s.transactor == nil {
+		return nil, s.unsupported("storage does not support locks")
+	}
+
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		return nil, invalidArgument("ttl", "ttl must be positive")
+	}
+
+	// waiter is woken up by release events, polling covers storages that do not report expiration.
+	var (
+		w      *watcher
+		events <-chan *pb.WatchEvent
+		done   <-chan struct{}
+	)
+	if req.GetWait() {
+		w, err = s.watchers.subscribe(req.GetKey(), false)
+		if err != nil {
+			return nil, err
+		}
+		defer s.watchers.unsubscribe(w)
+
+		events, done = w.events, w.done
+	}
+
+	interval := lockPollMinInterval
+	for {
+		token, acquired, err := s.tryLock(req.GetKey(), ttl)
+		if err != nil {
+			return nil, err
+		}
+		if acquired || !req.GetWait() {
+			return &pb.LockResult{
+				Acquired: acquired,
+				Token:    token,
+			}, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-done:
+			if w.err == errWatchHubClosed {
+				timer.Stop()
+				return nil, w.err
+			}
+			events, done = nil, nil // lagged behind, keep polling.
+		case <-events:
+		case <-timer.C:
+		}
+		timer.Stop()
+
+		interval *= 2
+		if interval > lockPollMaxInterval {
+			interval = lockPollMaxInterval
+		}
+	}
+}
+
+// tryLock allocates a fencing token and stores it in the lock key if the key does not exist. A held lock is checked
+// first, so that polling does not use up tokens. The counter expires along with the lock to not pile up, its ttl is
+// refreshed on every increment and lock refresh, and its changes are not published to watchers. A missing counter is
+// created with current unix time in nanoseconds but not below the last token handed out by this server, so tokens
+// never decrease even if the counter expires, is evicted or lost on restart while the clock goes backwards. Across
+// server restarts only the clock keeps them growing.
+func (s *Server) tryLock(key string, ttl time.Duration) (uint64, bool, error) {
+	_, err := s.storage.Get(key)
+	if err == nil {
+		return 0, false, nil
+	}
+	if !isNotFound(err) {
+		return 0, false, s.storageError(errCode(err), key, err, "failed to get lock")
+	}
+
+	initial := uint64(time.Now().UnixNano())
+	if last := atomic.LoadUint64(&s.lastToken); initial <= last {
+		initial = last + 1
+	}
+
+	token, err := s.unobserved.Increment(fenceKey(key), 1, &initial, ttl)
+	if err != nil {
+		return 0, false, s.storageError(errCode(err), fenceKey(key), err, "failed to allocate fencing token")
+	}
+	s.handedOut(token)
+
+	if token != initial { // counter existed, ttl is set only on creation.
+		err = s.unobserved.Touch(fenceKey(key), ttl)
+		if err != nil && !isNotFound(err) {
+			return 0, false, s.storageError(errCode(err), fenceKey(key), err, "failed to refresh fencing counter")
+		}
+	}
+
+	err = s.storage.Add(key, []byte(strconv.FormatUint(token, 10)), 0, ttl)
+	if implements[interface{ NotStoredErrorMarker() }](err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, s.storageError(errCode(err), key, err, "failed to acquire lock")
+	}
+
+	return token, true, nil
+}
+
+// handedOut raises lastToken to token.
+func (s *Server) handedOut(token uint64) {
+	for {
+		last := atomic.LoadUint64(&s.lastToken)
+		if token <= last || atomic.CompareAndSwapUint64(&s.lastToken, last, token) {
+			return
+		}
+	}
+}
+
+func (s *Server) Unlock(ctx context.Context, req *pb.UnlockRequest) (*pb.UnlockResult, error) {
+	if s.transactor == nil {
+		return nil, s.unsupported("storage does not support locks")
+	}
+
+	item, err := s.heldLock(req.GetKey(), req.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.watchers.lockKeys(req.GetKey())()
+
+	res, err := s.transactor.Txn(storage.Txn{
+		Compares: []storage.Compare{{Key: req.GetKey(), Target: storage.CompareVersion, Version: item.Version}},
+		Success:  []storage.Op{{Type: storage.OpDelete, Key: req.GetKey()}},
+	})
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to release lock")
+	}
+	if !res.Succeeded {
+		return nil, s.newError(codes.FailedPrecondition, pb.ErrorReason_ERROR_REASON_LOCK_NOT_HELD, req.GetKey(), "lock was changed concurrently")
+	}
+
+	s.watchers.deleted(req.GetKey())
+
+	return &pb.UnlockResult{}, nil
+}
+
+func (s *Server) RefreshLock(ctx context.Context, req *pb.RefreshLockRequest) (*pb.RefreshLockResult, error) {
+	if s.transactor == nil {
+		return nil, s.unsupported("storage does not support locks")
+	}
+
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		return nil, invalidArgument("ttl", "ttl must be positive")
+	}
+
+	item, err := s.heldLock(req.GetKey(), req.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.CompareAndSwap(req.GetKey(), item.Value, item.Flags, item.Version, ttl)
+	if err != nil {
+		return nil, s.storageError(errCode(err), req.GetKey(), err, "failed to refresh lock")
+	}
+
+	// the counter should outlive the lock, a missing one is recreated by the next tryLock.
+	err = s.unobserved.Touch(fenceKey(req.GetKey()), ttl)
+	if err != nil && !isNotFound(err) {
+		s.logger.Error().Err(err).Str("key", req.GetKey()).Msg("failed to refresh fencing counter")
+	}
+
+	return &pb.RefreshLockResult{}, nil
+}
+
+// heldLock returns the lock item if it holds token.
+func (s *Server) heldLock(key string, token uint64) (storage.Item, error) {
+	item, err := s.storage.Get(key)
+	if err != nil {
+		return storage.Item{}, s.storageError(errCode(err), key, err, "failed to get lock")
+	}
+
+	if string(item.Value) != strconv.FormatUint(token, 10) {
+		return storage.Item{}, s.newError(codes.FailedPrecondition, pb.ErrorReason_ERROR_REASON_LOCK_NOT_HELD, key, "lock is held with another token")
+	}
+
+	return item, nil
+}
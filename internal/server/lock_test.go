@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+func TestLock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	transactor := servermocks.NewMockITransactor(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, struct {
+		IStorage
+		ITransactor
+	}{storage, transactor}, nil)
+
+	t.Run("acquire", func(t *testing.T) {
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{}, storagepkg.ErrNotFound)
+		storage.EXPECT().Increment(fenceKey("lock"), uint64(1), gomock.Not(nil), time.Minute).Return(uint64(7), nil)
+		storage.EXPECT().Touch(fenceKey("lock"), time.Minute).Return(nil)
+		storage.EXPECT().Add("lock", []byte("7"), uint32(0), time.Minute).Return(nil)
+		res, err := server.Lock(context.Background(), &pb.LockRequest{Key: "lock", Ttl: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		require.Equal(t, &pb.LockResult{Acquired: true, Token: 7}, res)
+	})
+
+	t.Run("held", func(t *testing.T) {
+		// no token is allocated while the lock is held.
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{Value: []byte("7")}, nil)
+		res, err := server.Lock(context.Background(), &pb.LockRequest{Key: "lock", Ttl: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		require.Equal(t, &pb.LockResult{}, res)
+
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{}, storagepkg.ErrNotFound)
+		storage.EXPECT().Increment(fenceKey("lock"), uint64(1), gomock.Not(nil), time.Minute).Return(uint64(8), nil)
+		storage.EXPECT().Touch(fenceKey("lock"), time.Minute).Return(nil)
+		storage.EXPECT().Add("lock", []byte("8"), uint32(0), time.Minute).Return(storagepkg.ErrNotStored)
+		res, err = server.Lock(context.Background(), &pb.LockRequest{Key: "lock", Ttl: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		require.Equal(t, &pb.LockResult{}, res)
+	})
+
+	t.Run("tokens do not decrease when counter expires", func(t *testing.T) {
+		// counter was created by a clock that is an hour ahead.
+		ahead := uint64(time.Now().Add(time.Hour).UnixNano())
+		storage.EXPECT().Get("ahead").Return(storagepkg.Item{}, storagepkg.ErrNotFound)
+		storage.EXPECT().Increment(fenceKey("ahead"), uint64(1), gomock.Not(nil), time.Minute).Return(ahead, nil)
+		storage.EXPECT().Touch(fenceKey("ahead"), time.Minute).Return(nil)
+		storage.EXPECT().Add("ahead", gomock.Any(), uint32(0), time.Minute).Return(nil)
+		res, err := server.Lock(context.Background(), &pb.LockRequest{Key: "ahead", Ttl: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		require.Equal(t, ahead, res.GetToken())
+
+		// the counter has expired, it is created again with a lower clock.
+		storage.EXPECT().Get("ahead").Return(storagepkg.Item{}, storagepkg.ErrNotFound)
+		storage.EXPECT().Increment(fenceKey("ahead"), uint64(1), gomock.Not(nil), time.Minute).DoAndReturn(
+			func(_ string, _ uint64, initial *uint64, _ time.Duration) (uint64, error) {
+				return *initial, nil
+			})
+		storage.EXPECT().Add("ahead", gomock.Any(), uint32(0), time.Minute).Return(nil)
+		res, err = server.Lock(context.Background(), &pb.LockRequest{Key: "ahead", Ttl: durationpb.New(time.Minute)})
+		require.NoError(t, err)
+		require.Greater(t, res.GetToken(), ahead)
+	})
+
+	t.Run("wait for release", func(t *testing.T) {
+		gomock.InOrder(
+			storage.EXPECT().Get("lock").DoAndReturn(func(string) (storagepkg.Item, error) {
+				go server.watchers.deleted("lock")
+				return storagepkg.Item{Value: []byte("8")}, nil
+			}),
+			storage.EXPECT().Get("lock").Return(storagepkg.Item{}, storagepkg.ErrNotFound),
+			storage.EXPECT().Increment(fenceKey("lock"), uint64(1), gomock.Not(nil), time.Minute).Return(uint64(10), nil),
+			storage.EXPECT().Touch(fenceKey("lock"), time.Minute).Return(nil),
+			storage.EXPECT().Add("lock", []byte("10"), uint32(0), time.Minute).Return(nil),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		res, err := server.Lock(ctx, &pb.LockRequest{Key: "lock", Ttl: durationpb.New(time.Minute), Wait: true})
+		require.NoError(t, err)
+		require.Equal(t, &pb.LockResult{Acquired: true, Token: 10}, res)
+	})
+
+	t.Run("wait until deadline", func(t *testing.T) {
+		storage.EXPECT().Get("held").Return(storagepkg.Item{Value: []byte("10")}, nil).MinTimes(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := server.Lock(ctx, &pb.LockRequest{Key: "held", Ttl: durationpb.New(time.Minute), Wait: true})
+		require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{Value: []byte("7"), Version: 3}, nil)
+		transactor.EXPECT().Txn(storagepkg.Txn{
+			Compares: []storagepkg.Compare{{Key: "lock", Target: storagepkg.CompareVersion, Version: 3}},
+			Success:  []storagepkg.Op{{Type: storagepkg.OpDelete, Key: "lock"}},
+		}).Return(storagepkg.TxnResult{Succeeded: true}, nil)
+		_, err := server.Unlock(context.Background(), &pb.UnlockRequest{Key: "lock", Token: 7})
+		require.NoError(t, err)
+
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{Value: []byte("8"), Version: 4}, nil)
+		_, err = server.Unlock(context.Background(), &pb.UnlockRequest{Key: "lock", Token: 7})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{Value: []byte("7"), Version: 3}, nil)
+		transactor.EXPECT().Txn(gomock.Any()).Return(storagepkg.TxnResult{}, nil)
+		_, err = server.Unlock(context.Background(), &pb.UnlockRequest{Key: "lock", Token: 7})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		storage.EXPECT().Get("lock").Return(storagepkg.Item{Value: []byte("7"), Version: 3}, nil)
+		storage.EXPECT().CompareAndSwap("lock", []byte("7"), uint32(0), uint64(3), time.Hour).Return(nil)
+		storage.EXPECT().Touch(fenceKey("lock"), time.Hour).Return(storagepkg.ErrNotFound)
+		_, err := server.RefreshLock(context.Background(), &pb.RefreshLockRequest{Key: "lock", Token: 7, Ttl: durationpb.New(time.Hour)})
+		require.NoError(t, err)
+
+		_, err = server.RefreshLock(context.Background(), &pb.RefreshLockRequest{Key: "lock", Token: 7})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unsupported storage", func(t *testing.T) {
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+		_, err := server.Lock(context.Background(), &pb.LockRequest{Key: "lock", Ttl: durationpb.New(time.Minute)})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = server.Unlock(context.Background(), &pb.UnlockRequest{Key: "lock", Token: 7})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = server.RefreshLock(context.Background(), &pb.RefreshLockRequest{Key: "lock", Token: 7, Ttl: durationpb.New(time.Hour)})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
@@ -1,12 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/IlyaFloppy/grpcstore/internal/server (interfaces: IStorage)
+// Source: github.com/IlyaFloppy/grpcstore/internal/server (interfaces: IStorage,ILister,IStreamer,ITransactor,ILeaseManager,IStater,IHealthChecker,IStatsReporter)
 
 // Package mock_server is a generated GoMock package.
 package mock_server
 
 import (
 	reflect "reflect"
+	time "time"
 
+	storage "github.com/IlyaFloppy/grpcstore/internal/storage"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -33,6 +35,63 @@ func (m *MockIStorage) EXPECT() *MockIStorageMockRecorder {
 	return m.recorder
 }
 
+// Add mocks base method.
+func (m *MockIStorage) Add(arg0 string, arg1 []byte, arg2 uint32, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockIStorageMockRecorder) Add(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockIStorage)(nil).Add), arg0, arg1, arg2, arg3)
+}
+
+// Append mocks base method.
+func (m *MockIStorage) Append(arg0 string, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockIStorageMockRecorder) Append(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockIStorage)(nil).Append), arg0, arg1)
+}
+
+// CompareAndSwap mocks base method.
+func (m *MockIStorage) CompareAndSwap(arg0 string, arg1 []byte, arg2 uint32, arg3 uint64, arg4 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwap", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSwap indicates an expected call of CompareAndSwap.
+func (mr *MockIStorageMockRecorder) CompareAndSwap(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwap", reflect.TypeOf((*MockIStorage)(nil).CompareAndSwap), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Decrement mocks base method.
+func (m *MockIStorage) Decrement(arg0 string, arg1 uint64, arg2 *uint64, arg3 time.Duration) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockIStorageMockRecorder) Decrement(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockIStorage)(nil).Decrement), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method.
 func (m *MockIStorage) Delete(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -48,10 +107,10 @@ func (mr *MockIStorageMockRecorder) Delete(arg0 interface{}) *gomock.Call {
 }
 
 // Get mocks base method.
-func (m *MockIStorage) Get(arg0 string) ([]byte, error) {
+func (m *MockIStorage) Get(arg0 string) (storage.Item, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0)
-	ret0, _ := ret[0].([]byte)
+	ret0, _ := ret[0].(storage.Item)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -62,16 +121,508 @@ func (mr *MockIStorageMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIStorage)(nil).Get), arg0)
 }
 
+// GetAndTouch mocks base method.
+func (m *MockIStorage) GetAndTouch(arg0 string, arg1 time.Duration) (storage.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndTouch", arg0, arg1)
+	ret0, _ := ret[0].(storage.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAndTouch indicates an expected call of GetAndTouch.
+func (mr *MockIStorageMockRecorder) GetAndTouch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndTouch", reflect.TypeOf((*MockIStorage)(nil).GetAndTouch), arg0, arg1)
+}
+
+// Increment mocks base method.
+func (m *MockIStorage) Increment(arg0 string, arg1 uint64, arg2 *uint64, arg3 time.Duration) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockIStorageMockRecorder) Increment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockIStorage)(nil).Increment), arg0, arg1, arg2, arg3)
+}
+
+// MultiDelete mocks base method.
+func (m *MockIStorage) MultiDelete(arg0 []string) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiDelete", arg0)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MultiDelete indicates an expected call of MultiDelete.
+func (mr *MockIStorageMockRecorder) MultiDelete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiDelete", reflect.TypeOf((*MockIStorage)(nil).MultiDelete), arg0)
+}
+
+// MultiGet mocks base method.
+func (m *MockIStorage) MultiGet(arg0 []string) (map[string]storage.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiGet", arg0)
+	ret0, _ := ret[0].(map[string]storage.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MultiGet indicates an expected call of MultiGet.
+func (mr *MockIStorageMockRecorder) MultiGet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiGet", reflect.TypeOf((*MockIStorage)(nil).MultiGet), arg0)
+}
+
+// MultiSet mocks base method.
+func (m *MockIStorage) MultiSet(arg0 []storage.Entry) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiSet", arg0)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MultiSet indicates an expected call of MultiSet.
+func (mr *MockIStorageMockRecorder) MultiSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiSet", reflect.TypeOf((*MockIStorage)(nil).MultiSet), arg0)
+}
+
+// Prepend mocks base method.
+func (m *MockIStorage) Prepend(arg0 string, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepend", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prepend indicates an expected call of Prepend.
+func (mr *MockIStorageMockRecorder) Prepend(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepend", reflect.TypeOf((*MockIStorage)(nil).Prepend), arg0, arg1)
+}
+
+// Replace mocks base method.
+func (m *MockIStorage) Replace(arg0 string, arg1 []byte, arg2 uint32, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockIStorageMockRecorder) Replace(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockIStorage)(nil).Replace), arg0, arg1, arg2, arg3)
+}
+
 // Set mocks base method.
-func (m *MockIStorage) Set(arg0 string, arg1 []byte) error {
+func (m *MockIStorage) Set(arg0 string, arg1 []byte, arg2 uint32, arg3 time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", arg0, arg1)
+	ret := m.ctrl.Call(m, "Set", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockIStorageMockRecorder) Set(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIStorageMockRecorder) Set(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockIStorage)(nil).Set), arg0, arg1, arg2, arg3)
+}
+
+// Touch mocks base method.
+func (m *MockIStorage) Touch(arg0 string, arg1 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockIStorageMockRecorder) Touch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockIStorage)(nil).Touch), arg0, arg1)
+}
+
+// MockILister is a mock of ILister interface.
+type MockILister struct {
+	ctrl     *gomock.Controller
+	recorder *MockIListerMockRecorder
+}
+
+// MockIListerMockRecorder is the mock recorder for MockILister.
+type MockIListerMockRecorder struct {
+	mock *MockILister
+}
+
+// NewMockILister creates a new mock instance.
+func NewMockILister(ctrl *gomock.Controller) *MockILister {
+	mock := &MockILister{ctrl: ctrl}
+	mock.recorder = &MockIListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILister) EXPECT() *MockIListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockILister) List(arg0, arg1 string, arg2 int, arg3 bool) ([]storage.KeyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.KeyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIListerMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockILister)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// MockIStreamer is a mock of IStreamer interface.
+type MockIStreamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStreamerMockRecorder
+}
+
+// MockIStreamerMockRecorder is the mock recorder for MockIStreamer.
+type MockIStreamerMockRecorder struct {
+	mock *MockIStreamer
+}
+
+// NewMockIStreamer creates a new mock instance.
+func NewMockIStreamer(ctrl *gomock.Controller) *MockIStreamer {
+	mock := &MockIStreamer{ctrl: ctrl}
+	mock.recorder = &MockIStreamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStreamer) EXPECT() *MockIStreamerMockRecorder {
+	return m.recorder
+}
+
+// GetStream mocks base method.
+func (m *MockIStreamer) GetStream(arg0 string) (storage.ChunkReader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStream", arg0)
+	ret0, _ := ret[0].(storage.ChunkReader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStream indicates an expected call of GetStream.
+func (mr *MockIStreamerMockRecorder) GetStream(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStream", reflect.TypeOf((*MockIStreamer)(nil).GetStream), arg0)
+}
+
+// PutStream mocks base method.
+func (m *MockIStreamer) PutStream(arg0 string, arg1 uint32, arg2 time.Duration) (storage.ChunkWriter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutStream", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.ChunkWriter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutStream indicates an expected call of PutStream.
+func (mr *MockIStreamerMockRecorder) PutStream(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutStream", reflect.TypeOf((*MockIStreamer)(nil).PutStream), arg0, arg1, arg2)
+}
+
+// MockITransactor is a mock of ITransactor interface.
+type MockITransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockITransactorMockRecorder
+}
+
+// MockITransactorMockRecorder is the mock recorder for MockITransactor.
+type MockITransactorMockRecorder struct {
+	mock *MockITransactor
+}
+
+// NewMockITransactor creates a new mock instance.
+func NewMockITransactor(ctrl *gomock.Controller) *MockITransactor {
+	mock := &MockITransactor{ctrl: ctrl}
+	mock.recorder = &MockITransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITransactor) EXPECT() *MockITransactorMockRecorder {
+	return m.recorder
+}
+
+// Txn mocks base method.
+func (m *MockITransactor) Txn(arg0 storage.Txn) (storage.TxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Txn", arg0)
+	ret0, _ := ret[0].(storage.TxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Txn indicates an expected call of Txn.
+func (mr *MockITransactorMockRecorder) Txn(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Txn", reflect.TypeOf((*MockITransactor)(nil).Txn), arg0)
+}
+
+// MockILeaseManager is a mock of ILeaseManager interface.
+type MockILeaseManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockILeaseManagerMockRecorder
+}
+
+// MockILeaseManagerMockRecorder is the mock recorder for MockILeaseManager.
+type MockILeaseManagerMockRecorder struct {
+	mock *MockILeaseManager
+}
+
+// NewMockILeaseManager creates a new mock instance.
+func NewMockILeaseManager(ctrl *gomock.Controller) *MockILeaseManager {
+	mock := &MockILeaseManager{ctrl: ctrl}
+	mock.recorder = &MockILeaseManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILeaseManager) EXPECT() *MockILeaseManagerMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockILeaseManager) Attach(arg0 int64, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockILeaseManagerMockRecorder) Attach(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockILeaseManager)(nil).Attach), arg0, arg1)
+}
+
+// Detach mocks base method.
+func (m *MockILeaseManager) Detach(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Detach", arg0)
+}
+
+// Detach indicates an expected call of Detach.
+func (mr *MockILeaseManagerMockRecorder) Detach(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockILeaseManager)(nil).Detach), arg0)
+}
+
+// Exists mocks base method.
+func (m *MockILeaseManager) Exists(arg0 int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockILeaseManagerMockRecorder) Exists(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockILeaseManager)(nil).Exists), arg0)
+}
+
+// Grant mocks base method.
+func (m *MockILeaseManager) Grant(arg0 time.Duration) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", arg0)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockILeaseManagerMockRecorder) Grant(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockILeaseManager)(nil).Grant), arg0)
+}
+
+// KeepAlive mocks base method.
+func (m *MockILeaseManager) KeepAlive(arg0 int64) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeepAlive", arg0)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeepAlive indicates an expected call of KeepAlive.
+func (mr *MockILeaseManagerMockRecorder) KeepAlive(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepAlive", reflect.TypeOf((*MockILeaseManager)(nil).KeepAlive), arg0)
+}
+
+// LockKey mocks base method.
+func (m *MockILeaseManager) LockKey(arg0 string) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockKey", arg0)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// LockKey indicates an expected call of LockKey.
+func (mr *MockILeaseManagerMockRecorder) LockKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockKey", reflect.TypeOf((*MockILeaseManager)(nil).LockKey), arg0)
+}
+
+// OnExpire mocks base method.
+func (m *MockILeaseManager) OnExpire(arg0 func(string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnExpire", arg0)
+}
+
+// OnExpire indicates an expected call of OnExpire.
+func (mr *MockILeaseManagerMockRecorder) OnExpire(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnExpire", reflect.TypeOf((*MockILeaseManager)(nil).OnExpire), arg0)
+}
+
+// Revoke mocks base method.
+func (m *MockILeaseManager) Revoke(arg0 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockILeaseManagerMockRecorder) Revoke(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockILeaseManager)(nil).Revoke), arg0)
+}
+
+// MockIStater is a mock of IStater interface.
+type MockIStater struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStaterMockRecorder
+}
+
+// MockIStaterMockRecorder is the mock recorder for MockIStater.
+type MockIStaterMockRecorder struct {
+	mock *MockIStater
+}
+
+// NewMockIStater creates a new mock instance.
+func NewMockIStater(ctrl *gomock.Controller) *MockIStater {
+	mock := &MockIStater{ctrl: ctrl}
+	mock.recorder = &MockIStaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStater) EXPECT() *MockIStaterMockRecorder {
+	return m.recorder
+}
+
+// Stat mocks base method.
+func (m *MockIStater) Stat(arg0 string) (storage.Meta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0)
+	ret0, _ := ret[0].(storage.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockIStaterMockRecorder) Stat(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockIStater)(nil).Stat), arg0)
+}
+
+// MockIHealthChecker is a mock of IHealthChecker interface.
+type MockIHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHealthCheckerMockRecorder
+}
+
+// MockIHealthCheckerMockRecorder is the mock recorder for MockIHealthChecker.
+type MockIHealthCheckerMockRecorder struct {
+	mock *MockIHealthChecker
+}
+
+// NewMockIHealthChecker creates a new mock instance.
+func NewMockIHealthChecker(ctrl *gomock.Controller) *MockIHealthChecker {
+	mock := &MockIHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockIHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHealthChecker) EXPECT() *MockIHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method.
+func (m *MockIHealthChecker) Ping() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockIHealthCheckerMockRecorder) Ping() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockIHealthChecker)(nil).Ping))
+}
+
+// MockIStatsReporter is a mock of IStatsReporter interface.
+type MockIStatsReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStatsReporterMockRecorder
+}
+
+// MockIStatsReporterMockRecorder is the mock recorder for MockIStatsReporter.
+type MockIStatsReporterMockRecorder struct {
+	mock *MockIStatsReporter
+}
+
+// NewMockIStatsReporter creates a new mock instance.
+func NewMockIStatsReporter(ctrl *gomock.Controller) *MockIStatsReporter {
+	mock := &MockIStatsReporter{ctrl: ctrl}
+	mock.recorder = &MockIStatsReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStatsReporter) EXPECT() *MockIStatsReporterMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockIStatsReporter) Stats() (storage.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(storage.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockIStatsReporterMockRecorder) Stats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockIStorage)(nil).Set), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockIStatsReporter)(nil).Stats))
 }
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+const defaultPipelineConcurrency = 64
+
+func (s *Server) Pipeline(stream pb.GRPCStoreService_PipelineServer) error {
+	var (
+		wg      sync.WaitGroup
+		sendMu  sync.Mutex
+		sendErr error
+	)
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel() // runs before wg.Wait to abort in-flight operations when the stream fails.
+
+	// receiving stops while all slots are busy, so a client that sends faster than the storage can serve is slowed
+	// down by grpc flow control instead of piling up goroutines.
+	slots := make(chan struct{}, s.pipelineConcurrency)
+
+	send := func(res *pb.PipelineResult) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+
+		if sendErr != nil {
+			return
+		}
+
+		sendErr = stream.Send(res)
+		if sendErr != nil {
+			cancel()
+		}
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			wg.Wait()
+
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			return sendErr
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			send(s.pipelineOp(ctx, req))
+		}()
+	}
+}
+
+func (s *Server) pipelineOp(ctx context.Context, req *pb.PipelineRequest) (res *pb.PipelineResult) {
+	res = &pb.PipelineResult{
+		Tag: req.GetTag(),
+	}
+
+	defer func() {
+		if r := recover(); r != nil { // recovery interceptor does not see panics of this goroutine.
+			s.logger.Error().Interface("panic", r).Uint64("tag", req.GetTag()).Msg("recovered panic in pipeline")
+			res.Code = uint32(codes.Internal)
+			res.Message = "panic occurred"
+			res.Result = nil
+		}
+	}()
+
+	var err error
+	switch op := req.GetOp().(type) {
+	case *pb.PipelineRequest_Get:
+		var r *pb.GetResult
+		r, err = s.Get(ctx, op.Get)
+		res.Result = &pb.PipelineResult_Get{Get: r}
+	case *pb.PipelineRequest_Set:
+		var r *pb.SetResult
+		r, err = s.Set(ctx, op.Set)
+		res.Result = &pb.PipelineResult_Set{Set: r}
+	case *pb.PipelineRequest_Delete:
+		var r *pb.DeleteResult
+		r, err = s.Delete(ctx, op.Delete)
+		res.Result = &pb.PipelineResult_Delete{Delete: r}
+	default:
+		err = invalidArgument("op", "operation is not set")
+	}
+
+	if err != nil {
+		st := status.Convert(err)
+		res.Code = uint32(st.Code())
+		res.Message = st.Message()
+		res.Result = nil
+	}
+
+	return res
+}
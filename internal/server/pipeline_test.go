@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+type pipelineStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests chan *pb.PipelineRequest
+
+	mu      sync.Mutex
+	results []*pb.PipelineResult
+}
+
+func (s *pipelineStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *pipelineStream) Recv() (*pb.PipelineRequest, error) {
+	select {
+	case req, ok := <-s.requests:
+		if !ok {
+			return nil, io.EOF
+		}
+		return req, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *pipelineStream) Send(res *pb.PipelineResult) error {
+	s.mu.Lock()
+	s.results = append(s.results, res)
+	s.mu.Unlock()
+	return nil
+}
+
+func TestPipeline(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{PipelineConcurrency: 2}, storage, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		storage.EXPECT().Get("a").Return(storagepkg.Item{Value: []byte("1"), Version: 3}, nil)
+		storage.EXPECT().Set("b", []byte("2"), uint32(0), time.Duration(0)).Return(nil)
+		storage.EXPECT().Delete("c").Return(nil)
+		storage.EXPECT().Get("d").Return(storagepkg.Item{}, errors.New("failed on purpose"))
+
+		stream := &pipelineStream{
+			ctx:      context.Background(),
+			requests: make(chan *pb.PipelineRequest, 5),
+		}
+		stream.requests <- &pb.PipelineRequest{Tag: 1, Op: &pb.PipelineRequest_Get{Get: &pb.GetRequest{Key: "a"}}}
+		stream.requests <- &pb.PipelineRequest{Tag: 2, Op: &pb.PipelineRequest_Set{Set: &pb.SetRequest{Key: "b", Value: []byte("2")}}}
+		stream.requests <- &pb.PipelineRequest{Tag: 3, Op: &pb.PipelineRequest_Delete{Delete: &pb.DeleteRequest{Key: "c"}}}
+		stream.requests <- &pb.PipelineRequest{Tag: 4, Op: &pb.PipelineRequest_Get{Get: &pb.GetRequest{Key: "d"}}}
+		stream.requests <- &pb.PipelineRequest{Tag: 5}
+		close(stream.requests)
+
+		require.NoError(t, server.Pipeline(stream))
+
+		sort.Slice(stream.results, func(i, j int) bool {
+			return stream.results[i].Tag < stream.results[j].Tag
+		})
+		require.Len(t, stream.results, 5)
+		require.Equal(t, &pb.PipelineResult{Tag: 1, Result: &pb.PipelineResult_Get{Get: &pb.GetResult{Value: []byte("1"), Version: 3}}}, stream.results[0])
+		require.Equal(t, &pb.PipelineResult{Tag: 2, Result: &pb.PipelineResult_Set{Set: &pb.SetResult{}}}, stream.results[1])
+		require.Equal(t, &pb.PipelineResult{Tag: 3, Result: &pb.PipelineResult_Delete{Delete: &pb.DeleteResult{}}}, stream.results[2])
+		require.Equal(t, uint64(4), stream.results[3].Tag)
+		require.Equal(t, uint32(codes.Internal), stream.results[3].Code)
+		require.Nil(t, stream.results[3].Result)
+		require.Equal(t, uint64(5), stream.results[4].Tag)
+		require.Equal(t, uint32(codes.InvalidArgument), stream.results[4].Code)
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		release := make(chan struct{})
+		var inFlight, maxInFlight int
+		var mu sync.Mutex
+		storage.EXPECT().Get(gomock.Any()).Times(10).DoAndReturn(func(key string) (storagepkg.Item, error) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			<-release
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return storagepkg.Item{}, nil
+		})
+
+		stream := &pipelineStream{
+			ctx:      context.Background(),
+			requests: make(chan *pb.PipelineRequest, 10),
+		}
+		for i := 0; i < 10; i++ {
+			stream.requests <- &pb.PipelineRequest{Tag: uint64(i), Op: &pb.PipelineRequest_Get{Get: &pb.GetRequest{Key: "key"}}}
+		}
+		close(stream.requests)
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- server.Pipeline(stream)
+		}()
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return inFlight == 2
+		}, time.Second, time.Millisecond)
+		close(release)
+
+		require.NoError(t, <-errCh)
+		require.Len(t, stream.results, 10)
+		require.Equal(t, 2, maxInFlight)
+	})
+
+	t.Run("canceled stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &pipelineStream{
+			ctx:      ctx,
+			requests: make(chan *pb.PipelineRequest),
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- server.Pipeline(stream)
+		}()
+		cancel()
+
+		require.ErrorIs(t, <-errCh, context.Canceled)
+	})
+}
@@ -3,11 +3,15 @@ package server
 import (
 	"context"
 	"net"
+	"time"
 
 	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 
 	"github.com/IlyaFloppy/grpcstore/internal/config"
 	"github.com/IlyaFloppy/grpcstore/internal/server/interceptors"
@@ -15,19 +19,85 @@ import (
 )
 
 type Server struct {
+	lastToken uint64 // highest fencing token handed out, accessed atomically and kept first to be 64-bit aligned.
+
 	pb.UnimplementedGRPCStoreServiceServer
+	pb.UnimplementedAdminServiceServer
 
 	logger     zerolog.Logger
 	cfg        config.ServerConfig
 	grpcServer *grpc.Server
 	readyCh    chan struct{}
 	storage    IStorage
+	unobserved IStorage      // storage without watch events for keys in the reserved namespace.
+	backend    string        // name of the storage, reported in error details.
+	lister     ILister       // nil when storage can not list keys.
+	stater     IStater       // nil when storage can not describe keys without reading values.
+	streamer   IStreamer     // nil when storage keeps whole values, chunked writes are buffered then.
+	transactor ITransactor   // nil when storage does not support transactions.
+	leases     ILeaseManager // nil when leases are disabled.
+	watchers   *watchHub
+
+	calls         *callStats
+	statsReporter IStatsReporter // nil when storage does not report stats.
+
+	health              *health.Server
+	healthChecker       IHealthChecker // nil when storage has nothing to check.
+	healthCheckInterval time.Duration
+	storageReady        <-chan struct{} // closed when storage component is ready, server is not serving before it.
+
+	pipelineConcurrency int
 }
 
-func New(logger zerolog.Logger, cfg config.ServerConfig, storage IStorage) *Server {
+func New(logger zerolog.Logger, cfg config.ServerConfig, storage IStorage, leases ILeaseManager) *Server {
 	logger = logger.With().Str("component", (*Server)(nil).Name()).Logger()
 
-	return &Server{
+	watchers := newWatchHub()
+	if n, ok := storage.(IExpirationNotifier); ok {
+		n.OnExpire(watchers.expired)
+	}
+	if leases != nil {
+		leases.OnExpire(watchers.expired)
+	}
+
+	lister, _ := storage.(ILister)
+	stater, _ := storage.(IStater)
+	streamer, _ := storage.(IStreamer)
+	transactor, _ := storage.(ITransactor)
+
+	var backend string
+	if n, ok := storage.(interface{ Name() string }); ok {
+		backend = n.Name()
+	}
+
+	statsReporter, _ := storage.(IStatsReporter)
+
+	healthChecker, _ := storage.(IHealthChecker)
+	healthCheckInterval := cfg.HealthCheckInterval
+	if healthCheckInterval <= 0 {
+		healthCheckInterval = defaultHealthCheckInterval
+	}
+
+	var storageReady <-chan struct{}
+	if c, ok := storage.(interface{ ReadyCh() <-chan struct{} }); ok {
+		storageReady = c.ReadyCh()
+	} else {
+		ready := make(chan struct{})
+		close(ready)
+		storageReady = ready
+	}
+
+	pipelineConcurrency := cfg.PipelineConcurrency
+	if l, ok := storage.(IConcurrencyLimiter); ok {
+		pipelineConcurrency = l.Concurrency()
+	}
+	if pipelineConcurrency <= 0 {
+		pipelineConcurrency = defaultPipelineConcurrency
+	}
+
+	calls := newCallStats(time.Now())
+
+	s := &Server{
 		logger: logger,
 		cfg:    cfg,
 		grpcServer: grpc.NewServer(
@@ -38,17 +108,43 @@ func New(logger zerolog.Logger, cfg config.ServerConfig, storage IStorage) *Serv
 			grpc.WriteBufferSize(cfg.WriteBufferSize),
 			grpc.ReadBufferSize(cfg.ReadBufferSize),
 			grpc.UnaryInterceptor(grpcmiddleware.ChainUnaryServer(
+				calls.unaryInterceptor,
 				interceptors.WithLoggingUnaryInterceptor(logger),
 				interceptors.WithRecoveryUnaryInterceptor(logger),
 			)),
 			grpc.StreamInterceptor(grpcmiddleware.ChainStreamServer(
+				calls.streamInterceptor,
 				interceptors.WithLoggingStreamInterceptor(logger),
 				interceptors.WithRecoveryStreamInterceptor(logger),
 			)),
 		),
 		readyCh: make(chan struct{}),
-		storage: storage,
+		storage: &observedStorage{
+			IStorage: storage,
+			hub:      watchers,
+		},
+		unobserved: storage,
+		backend:    backend,
+		lister:     lister,
+		stater:     stater,
+		streamer:   streamer,
+		transactor: transactor,
+		leases:     leases,
+		watchers:   watchers,
+
+		calls:         calls,
+		statsReporter: statsReporter,
+
+		health:              health.NewServer(),
+		healthChecker:       healthChecker,
+		healthCheckInterval: healthCheckInterval,
+		storageReady:        storageReady,
+
+		pipelineConcurrency: pipelineConcurrency,
 	}
+	s.setServing(false)
+
+	return s
 }
 
 func (s *Server) Name() string {
@@ -62,9 +158,18 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 
 	pb.RegisterGRPCStoreServiceServer(s.grpcServer, s)
+	pb.RegisterAdminServiceServer(s.grpcServer, s)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	if s.cfg.Reflection {
+		reflection.Register(s.grpcServer)
+	}
+
+	go s.checkHealth(ctx)
 
 	go func() {
 		<-ctx.Done()
+		s.health.Shutdown() // probes see the server going away while in-flight calls are finished.
+		s.watchers.close()
 		s.grpcServer.GracefulStop()
 	}()
 
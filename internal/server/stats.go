@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+// statsWindow is the number of seconds over which ops per second are averaged, calls are counted per second of it.
+const statsWindow = 60
+
+type methodCalls struct {
+	total   uint64
+	seconds [statsWindow]int64 // unix second counted by the bucket at the same index.
+	counts  [statsWindow]uint64
+}
+
+// callStats counts calls of every grpc method and reads of keys made by clients since the server was created.
+type callStats struct {
+	hits   uint64 // updated atomically, first in the struct to be 64-bit aligned.
+	misses uint64 // updated atomically.
+
+	started time.Time
+
+	mu      sync.Mutex
+	methods map[string]*methodCalls
+}
+
+func newCallStats(now time.Time) *callStats {
+	return &callStats{
+		started: now,
+		methods: make(map[string]*methodCalls),
+	}
+}
+
+func (c *callStats) add(method string, now time.Time) {
+	sec := now.Unix()
+	i := sec % statsWindow
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.methods[method]
+	if !ok {
+		m = &methodCalls{}
+		c.methods[method] = m
+	}
+
+	m.total++
+	if m.seconds[i] != sec {
+		m.seconds[i] = sec
+		m.counts[i] = 0
+	}
+	m.counts[i]++
+}
+
+// read counts a client read of a single key. Reads made by the server itself, e.g. to publish watch events or to check
+// a lock, are not counted, so that they do not skew the hit ratio.
+func (c *callStats) read(err error) {
+	switch {
+	case err == nil:
+		atomic.AddUint64(&c.hits, 1)
+	case isNotFound(err):
+		atomic.AddUint64(&c.misses, 1)
+	}
+}
+
+func (c *callStats) reads(hits, misses int) {
+	atomic.AddUint64(&c.hits, uint64(hits))
+	atomic.AddUint64(&c.misses, uint64(misses))
+}
+
+// snapshot returns methods sorted by name. Rates are averaged over the uptime while it is shorter than the window.
+func (c *callStats) snapshot(now time.Time) []*pb.GetStatsResult_Method {
+	sec := now.Unix()
+	window := now.Sub(c.started).Seconds()
+	if window > statsWindow {
+		window = statsWindow
+	}
+	if window < 1 {
+		window = 1
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	methods := make([]*pb.GetStatsResult_Method, 0, len(c.methods))
+	for name, m := range c.methods {
+		var recent uint64
+		for i, s := range m.seconds {
+			if sec-s < statsWindow {
+				recent += m.counts[i]
+			}
+		}
+
+		methods = append(methods, &pb.GetStatsResult_Method{
+			Name:         name,
+			Calls:        m.total,
+			OpsPerSecond: float64(recent) / window,
+		})
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].GetName() < methods[j].GetName()
+	})
+
+	return methods
+}
+
+func (c *callStats) unaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	c.add(info.FullMethod, time.Now())
+	return handler(ctx, req)
+}
+
+func (c *callStats) streamInterceptor(
+	srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	c.add(info.FullMethod, time.Now())
+	return handler(srv, ss)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+type statsStorage struct {
+	IStorage
+	IStatsReporter
+}
+
+func TestCallStats(t *testing.T) {
+	started := time.Unix(1000, 0)
+	calls := newCallStats(started)
+
+	calls.add("/pb.GRPCStoreService/Set", started)
+	for i := 0; i < 10; i++ {
+		calls.add("/pb.GRPCStoreService/Get", started.Add(time.Duration(i)*time.Second))
+	}
+
+	require.Equal(t, []*pb.GetStatsResult_Method{
+		{Name: "/pb.GRPCStoreService/Get", Calls: 10, OpsPerSecond: 1},
+		{Name: "/pb.GRPCStoreService/Set", Calls: 1, OpsPerSecond: 0.1},
+	}, calls.snapshot(started.Add(10*time.Second)))
+
+	// calls older than the window are left out of the rate but not of the total.
+	calls.add("/pb.GRPCStoreService/Get", started.Add(100*time.Second))
+	require.Equal(t, []*pb.GetStatsResult_Method{
+		{Name: "/pb.GRPCStoreService/Get", Calls: 11, OpsPerSecond: 1.0 / statsWindow},
+		{Name: "/pb.GRPCStoreService/Set", Calls: 1, OpsPerSecond: 0},
+	}, calls.snapshot(started.Add(100*time.Second)))
+}
+
+func TestGetStats(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	t.Run("storage stats", func(t *testing.T) {
+		storage := servermocks.NewMockIStorage(ctrl)
+		reporter := servermocks.NewMockIStatsReporter(ctrl)
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, statsStorage{storage, reporter}, nil)
+
+		storage.EXPECT().Get("key").Return(storagepkg.Item{Value: []byte("12345")}, nil)
+		_, err := server.calls.unaryInterceptor(context.Background(), &pb.GetRequest{Key: "key"},
+			&grpc.UnaryServerInfo{FullMethod: "/pb.GRPCStoreService/Get"},
+			func(ctx context.Context, req any) (any, error) {
+				return server.Get(ctx, req.(*pb.GetRequest))
+			})
+		require.NoError(t, err)
+
+		storage.EXPECT().Get("missing").Return(storagepkg.Item{}, storagepkg.ErrNotFound)
+		_, err = server.Get(context.Background(), &pb.GetRequest{Key: "missing"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		storage.EXPECT().MultiGet([]string{"key", "missing"}).Return(map[string]storagepkg.Item{"key": {}}, nil)
+		_, err = server.MultiGet(context.Background(), &pb.MultiGetRequest{Keys: []string{"key", "missing"}})
+		require.NoError(t, err)
+
+		// reads made by the server itself are not counted.
+		storage.EXPECT().Get("key").Return(storagepkg.Item{}, nil)
+		_, err = server.storage.Get("key")
+		require.NoError(t, err)
+
+		reporter.EXPECT().Stats().Return(storagepkg.Stats{
+			Keys:  2,
+			Bytes: 10,
+			Raw:   map[string]string{"version": "1.6.15"},
+		}, nil)
+		res, err := server.GetStats(context.Background(), &pb.GetStatsRequest{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), res.GetKeyCount())
+		require.Equal(t, uint64(10), res.GetTotalBytes())
+		require.Equal(t, uint64(2), res.GetHits())
+		require.Equal(t, uint64(2), res.GetMisses())
+		require.Equal(t, 0.5, res.GetHitRatio())
+		require.Equal(t, map[string]string{"version": "1.6.15"}, res.GetBackendStats())
+		require.Positive(t, res.GetUptime().AsDuration())
+		require.Len(t, res.GetMethods(), 1)
+		require.Equal(t, "/pb.GRPCStoreService/Get", res.GetMethods()[0].GetName())
+		require.Equal(t, uint64(1), res.GetMethods()[0].GetCalls())
+
+		reporter.EXPECT().Stats().Return(storagepkg.Stats{}, storagepkg.ErrUnavailable)
+		_, err = server.GetStats(context.Background(), &pb.GetStatsRequest{})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("no storage stats", func(t *testing.T) {
+		storage := servermocks.NewMockIStorage(ctrl)
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+		res, err := server.GetStats(context.Background(), &pb.GetStatsRequest{})
+		require.NoError(t, err)
+		require.Zero(t, res.GetKeyCount())
+		require.Zero(t, res.GetHitRatio())
+		require.Empty(t, res.GetMethods())
+	})
+}
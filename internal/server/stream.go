@@ -0,0 +1,205 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+const streamChunkSize = 256 << 10 // 256KB, well below default grpc message size limit.
+
+func (s *Server) PutStream(stream pb.GRPCStoreService_PutStreamServer) error {
+	req, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return invalidArgument("", "stream must contain at least one message")
+	}
+	if err != nil {
+		return err
+	}
+
+	ttl, err := parseTTL(req.GetTtl())
+	if err != nil {
+		return err
+	}
+
+	key, flags := req.GetKey(), req.GetFlags()
+
+	w, err := s.chunkWriter(key, flags, ttl)
+	if err != nil {
+		return s.storageError(errCode(err), key, err, "failed to put key")
+	}
+
+	for {
+		err = w.Write(req.GetChunk())
+		if err != nil {
+			s.abort(w, key)
+			return s.storageError(errCode(err), key, err, "failed to write chunk")
+		}
+
+		req, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			s.abort(w, key)
+			return err
+		}
+	}
+
+	err = s.commit(w, key, flags)
+	if err != nil {
+		s.abort(w, key)
+		return s.storageError(errCode(err), key, err, "failed to put key")
+	}
+
+	return stream.SendAndClose(&pb.PutStreamResult{})
+}
+
+func (s *Server) GetStream(req *pb.GetStreamRequest, stream pb.GRPCStoreService_GetStreamServer) error {
+	r, err := s.chunkReader(req.GetKey())
+	if err != nil {
+		return s.storageError(errCode(err), req.GetKey(), err, "failed to get key")
+	}
+
+	item := r.Item()
+	res := &pb.GetStreamResult{
+		Size:    r.Size(),
+		Version: item.Version,
+		Flags:   item.Flags,
+	}
+	sent := false
+
+	for {
+		chunk, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return s.storageError(errCode(err), req.GetKey(), err, "failed to read chunk")
+		}
+
+		res.Chunk = chunk
+		err = stream.Send(res)
+		if err != nil {
+			return err
+		}
+
+		res = &pb.GetStreamResult{}
+		sent = true
+	}
+
+	if !sent { // empty value still has metadata.
+		return stream.Send(res)
+	}
+
+	return nil
+}
+
+func (s *Server) chunkWriter(key string, flags uint32, ttl time.Duration) (storage.ChunkWriter, error) {
+	if s.streamer != nil {
+		return s.streamer.PutStream(key, flags, ttl)
+	}
+
+	return &bufferedChunkWriter{
+		storage: s.storage,
+		key:     key,
+		flags:   flags,
+		ttl:     ttl,
+	}, nil
+}
+
+func (s *Server) chunkReader(key string) (storage.ChunkReader, error) {
+	if s.streamer != nil {
+		return s.streamer.GetStream(key)
+	}
+
+	item, err := s.storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bufferedChunkReader{item: item}, nil
+}
+
+// commit publishes values written by the streamer, buffered writes go through observed storage and are published there.
+func (s *Server) commit(w storage.ChunkWriter, key string, flags uint32) error {
+	if s.streamer == nil {
+		return w.Commit()
+	}
+
+	defer s.watchers.lockKeys(key)()
+
+	err := w.Commit()
+	if err != nil {
+		return err
+	}
+
+	s.watchers.put(key, nil, flags)
+
+	return nil
+}
+
+func (s *Server) abort(w storage.ChunkWriter, key string) {
+	err := w.Abort()
+	if err != nil {
+		s.logger.Warn().Err(err).Str("key", key).Msg("failed to abort chunked write")
+	}
+}
+
+// bufferedChunkWriter collects the whole value in memory for storages that have no limit on value size.
+type bufferedChunkWriter struct {
+	storage IStorage
+	key     string
+	flags   uint32
+	ttl     time.Duration
+	buf     []byte
+}
+
+func (w *bufferedChunkWriter) Write(chunk []byte) error {
+	w.buf = append(w.buf, chunk...)
+	return nil
+}
+
+func (w *bufferedChunkWriter) Commit() error {
+	return w.storage.Set(w.key, w.buf, w.flags, w.ttl)
+}
+
+func (w *bufferedChunkWriter) Abort() error {
+	w.buf = nil
+	return nil
+}
+
+type bufferedChunkReader struct {
+	item   storage.Item
+	offset int
+}
+
+func (r *bufferedChunkReader) Item() storage.Item {
+	return storage.Item{
+		Version: r.item.Version,
+		Flags:   r.item.Flags,
+	}
+}
+
+func (r *bufferedChunkReader) Size() uint64 {
+	return uint64(len(r.item.Value))
+}
+
+func (r *bufferedChunkReader) Next() ([]byte, error) {
+	if r.offset >= len(r.item.Value) {
+		return nil, io.EOF
+	}
+
+	end := r.offset + streamChunkSize
+	if end > len(r.item.Value) {
+		end = len(r.item.Value)
+	}
+
+	chunk := r.item.Value[r.offset:end]
+	r.offset = end
+
+	return chunk, nil
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+type putStream struct {
+	grpc.ServerStream
+	requests []*pb.PutStreamRequest
+	err      error // returned instead of io.EOF after all requests.
+	result   *pb.PutStreamResult
+}
+
+func (s *putStream) Recv() (*pb.PutStreamRequest, error) {
+	if len(s.requests) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *putStream) SendAndClose(res *pb.PutStreamResult) error {
+	s.result = res
+	return nil
+}
+
+type getStream struct {
+	grpc.ServerStream
+	results []*pb.GetStreamResult
+}
+
+func (s *getStream) Send(res *pb.GetStreamResult) error {
+	s.results = append(s.results, res)
+	return nil
+}
+
+type fakeChunkWriter struct {
+	written   []byte
+	committed bool
+	aborted   bool
+}
+
+func (w *fakeChunkWriter) Write(chunk []byte) error {
+	w.written = append(w.written, chunk...)
+	return nil
+}
+
+func (w *fakeChunkWriter) Commit() error {
+	w.committed = true
+	return nil
+}
+
+func (w *fakeChunkWriter) Abort() error {
+	w.aborted = true
+	return nil
+}
+
+func TestPutStream(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	t.Run("buffered", func(t *testing.T) {
+		storage := servermocks.NewMockIStorage(ctrl)
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+		storage.EXPECT().Set("key", []byte("123456"), uint32(7), time.Minute).Return(nil)
+		stream := &putStream{requests: []*pb.PutStreamRequest{
+			{Key: "key", Ttl: durationpb.New(time.Minute), Flags: 7, Chunk: []byte("12")},
+			{Chunk: []byte("34")},
+			{Key: "ignored", Chunk: []byte("56")},
+		}}
+		require.NoError(t, server.PutStream(stream))
+		require.NotNil(t, stream.result)
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		storage := servermocks.NewMockIStorage(ctrl)
+		streamer := servermocks.NewMockIStreamer(ctrl)
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, struct {
+			IStorage
+			IStreamer
+		}{storage, streamer}, nil)
+
+		w := &fakeChunkWriter{}
+		streamer.EXPECT().PutStream("key", uint32(0), time.Duration(0)).Return(w, nil)
+		stream := &putStream{requests: []*pb.PutStreamRequest{
+			{Key: "key", Chunk: []byte("12")},
+			{Chunk: []byte("34")},
+		}}
+		require.NoError(t, server.PutStream(stream))
+		require.Equal(t, []byte("1234"), w.written)
+		require.True(t, w.committed)
+		require.False(t, w.aborted)
+
+		w = &fakeChunkWriter{}
+		streamer.EXPECT().PutStream("key", uint32(0), time.Duration(0)).Return(w, nil)
+		stream = &putStream{
+			requests: []*pb.PutStreamRequest{{Key: "key", Chunk: []byte("12")}},
+			err:      status.Error(codes.Canceled, "canceled by client"),
+		}
+		require.Error(t, server.PutStream(stream))
+		require.False(t, w.committed)
+		require.True(t, w.aborted)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		storage := servermocks.NewMockIStorage(ctrl)
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+		err := server.PutStream(&putStream{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGetStream(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	t.Run("happy case", func(t *testing.T) {
+		value := bytes.Repeat([]byte("x"), streamChunkSize*2+1)
+		storage.EXPECT().Get("key").Return(storagepkg.Item{Value: value, Version: 42, Flags: 7}, nil)
+
+		stream := &getStream{}
+		require.NoError(t, server.GetStream(&pb.GetStreamRequest{Key: "key"}, stream))
+		require.Len(t, stream.results, 3)
+		require.Equal(t, uint64(len(value)), stream.results[0].Size)
+		require.Equal(t, uint64(42), stream.results[0].Version)
+		require.Equal(t, uint32(7), stream.results[0].Flags)
+		require.Zero(t, stream.results[1].Size)
+
+		var got []byte
+		for _, res := range stream.results {
+			got = append(got, res.Chunk...)
+		}
+		require.Equal(t, value, got)
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		storage.EXPECT().Get("key").Return(storagepkg.Item{Version: 42}, nil)
+
+		stream := &getStream{}
+		require.NoError(t, server.GetStream(&pb.GetStreamRequest{Key: "key"}, stream))
+		require.Equal(t, []*pb.GetStreamResult{{Version: 42}}, stream.results)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		storage.EXPECT().Get("key").Return(storagepkg.Item{}, storagepkg.ErrConflict)
+
+		err := server.GetStream(&pb.GetStreamRequest{Key: "key"}, &getStream{})
+		require.Equal(t, codes.Aborted, status.Code(err))
+	})
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+const maxTxnSize = 128 // total number of compares and ops.
+
+func (s *Server) Txn(ctx context.Context, req *pb.TxnRequest) (*pb.TxnResult, error) {
+	if s.transactor == nil {
+		return nil, s.unsupported("storage does not support transactions")
+	}
+
+	if len(req.GetCompares())+len(req.GetSuccess())+len(req.GetFailure()) > maxTxnSize {
+		return nil, invalidArgument("", fmt.Sprintf("transaction must have at most %d compares and ops", maxTxnSize))
+	}
+
+	txn := storage.Txn{
+		Compares: make([]storage.Compare, len(req.GetCompares())),
+	}
+
+	for i, c := range req.GetCompares() {
+		txn.Compares[i] = storage.Compare{Key: c.GetKey()}
+
+		switch target := c.GetTarget().(type) {
+		case *pb.Compare_Value:
+			txn.Compares[i].Target = storage.CompareValue
+			txn.Compares[i].Value = target.Value
+		case *pb.Compare_Version:
+			txn.Compares[i].Target = storage.CompareVersion
+			txn.Compares[i].Version = target.Version
+		case *pb.Compare_Exists:
+			txn.Compares[i].Target = storage.CompareExists
+			txn.Compares[i].Exists = target.Exists
+		default:
+			return nil, invalidArgument(fmt.Sprintf("compares[%d].target", i), fmt.Sprintf("compare %d has no target", i))
+		}
+	}
+
+	var err error
+	txn.Success, err = parseTxnOps("success", req.GetSuccess())
+	if err != nil {
+		return nil, err
+	}
+	txn.Failure, err = parseTxnOps("failure", req.GetFailure())
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.watchers.lockKeys(txnKeys(txn)...)()
+
+	res, err := s.transactor.Txn(txn)
+	if err != nil {
+		return nil, s.storageError(errCode(err), "", err, "failed to execute transaction")
+	}
+
+	ops := txn.Success
+	if !res.Succeeded {
+		ops = txn.Failure
+	}
+
+	results := make([]*pb.TxnOpResult, len(ops))
+	for i, op := range ops {
+		switch op.Type {
+		case storage.OpGet:
+			results[i] = &pb.TxnOpResult{}
+			if r := res.Results[i]; r.Found {
+				results[i].Result = &pb.TxnOpResult_Get{Get: &pb.GetResult{
+					Value:   r.Item.Value,
+					Version: r.Item.Version,
+					Flags:   r.Item.Flags,
+				}}
+			}
+		case storage.OpSet:
+			s.watchers.put(op.Key, op.Value, op.Flags)
+			results[i] = &pb.TxnOpResult{Result: &pb.TxnOpResult_Set{Set: &pb.SetResult{}}}
+		case storage.OpDelete:
+			s.detach(op.Key)
+			s.watchers.deleted(op.Key)
+			results[i] = &pb.TxnOpResult{Result: &pb.TxnOpResult_Delete{Delete: &pb.DeleteResult{}}}
+		}
+	}
+
+	guarantee := pb.TxnGuarantee_TXN_GUARANTEE_BEST_EFFORT
+	if res.Atomic {
+		guarantee = pb.TxnGuarantee_TXN_GUARANTEE_ATOMIC
+	}
+
+	return &pb.TxnResult{
+		Succeeded: res.Succeeded,
+		Results:   results,
+		Guarantee: guarantee,
+	}, nil
+}
+
+// txnKeys returns keys written by both branches as the executed one is known only after the transaction.
+func txnKeys(txn storage.Txn) []string {
+	var keys []string
+	for _, op := range append(txn.Success, txn.Failure...) {
+		if op.Type != storage.OpGet {
+			keys = append(keys, op.Key)
+		}
+	}
+
+	return keys
+}
+
+func parseTxnOps(field string, ops []*pb.TxnOp) ([]storage.Op, error) {
+	res := make([]storage.Op, len(ops))
+	for i, op := range ops {
+		switch op := op.GetOp().(type) {
+		case *pb.TxnOp_Get:
+			res[i] = storage.Op{Type: storage.OpGet, Key: op.Get.GetKey()}
+		case *pb.TxnOp_Set:
+			if op.Set.GetMode() != pb.SetMode_SET_MODE_SET {
+				return nil, invalidArgument(fmt.Sprintf("%s[%d].set.mode", field, i), fmt.Sprintf("op %d: only SET_MODE_SET is supported in transactions", i))
+			}
+			if op.Set.GetLease() != 0 {
+				return nil, invalidArgument(fmt.Sprintf("%s[%d].set.lease", field, i), fmt.Sprintf("op %d: leases are not supported in transactions", i))
+			}
+
+			ttl, err := parseTTL(op.Set.GetTtl())
+			if err != nil {
+				return nil, err
+			}
+
+			res[i] = storage.Op{
+				Type:  storage.OpSet,
+				Key:   op.Set.GetKey(),
+				Value: op.Set.GetValue(),
+				Flags: op.Set.GetFlags(),
+				TTL:   ttl,
+			}
+		case *pb.TxnOp_Delete:
+			res[i] = storage.Op{Type: storage.OpDelete, Key: op.Delete.GetKey()}
+		default:
+			return nil, invalidArgument(fmt.Sprintf("%s[%d].op", field, i), fmt.Sprintf("op %d is not set", i))
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+func TestTxn(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	transactor := servermocks.NewMockITransactor(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, struct {
+		IStorage
+		ITransactor
+	}{storage, transactor}, nil)
+
+	req := &pb.TxnRequest{
+		Compares: []*pb.Compare{
+			{Key: "from", Target: &pb.Compare_Version{Version: 42}},
+			{Key: "to", Target: &pb.Compare_Exists{Exists: false}},
+		},
+		Success: []*pb.TxnOp{
+			{Op: &pb.TxnOp_Delete{Delete: &pb.DeleteRequest{Key: "from"}}},
+			{Op: &pb.TxnOp_Set{Set: &pb.SetRequest{Key: "to", Value: []byte("item"), Ttl: durationpb.New(time.Minute)}}},
+		},
+		Failure: []*pb.TxnOp{
+			{Op: &pb.TxnOp_Get{Get: &pb.GetRequest{Key: "from"}}},
+			{Op: &pb.TxnOp_Get{Get: &pb.GetRequest{Key: "to"}}},
+		},
+	}
+	txn := storagepkg.Txn{
+		Compares: []storagepkg.Compare{
+			{Key: "from", Target: storagepkg.CompareVersion, Version: 42},
+			{Key: "to", Target: storagepkg.CompareExists, Exists: false},
+		},
+		Success: []storagepkg.Op{
+			{Type: storagepkg.OpDelete, Key: "from"},
+			{Type: storagepkg.OpSet, Key: "to", Value: []byte("item"), TTL: time.Minute},
+		},
+		Failure: []storagepkg.Op{
+			{Type: storagepkg.OpGet, Key: "from"},
+			{Type: storagepkg.OpGet, Key: "to"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		transactor.EXPECT().Txn(txn).Return(storagepkg.TxnResult{
+			Succeeded: true,
+			Results:   make([]storagepkg.OpResult, 2),
+			Atomic:    true,
+		}, nil)
+		res, err := server.Txn(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, &pb.TxnResult{
+			Succeeded: true,
+			Results: []*pb.TxnOpResult{
+				{Result: &pb.TxnOpResult_Delete{Delete: &pb.DeleteResult{}}},
+				{Result: &pb.TxnOpResult_Set{Set: &pb.SetResult{}}},
+			},
+			Guarantee: pb.TxnGuarantee_TXN_GUARANTEE_ATOMIC,
+		}, res)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		transactor.EXPECT().Txn(txn).Return(storagepkg.TxnResult{
+			Results: []storagepkg.OpResult{
+				{Item: storagepkg.Item{Value: []byte("item"), Version: 43}, Found: true},
+				{},
+			},
+		}, nil)
+		res, err := server.Txn(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, &pb.TxnResult{
+			Results: []*pb.TxnOpResult{
+				{Result: &pb.TxnOpResult_Get{Get: &pb.GetResult{Value: []byte("item"), Version: 43}}},
+				{},
+			},
+			Guarantee: pb.TxnGuarantee_TXN_GUARANTEE_BEST_EFFORT,
+		}, res)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		transactor.EXPECT().Txn(txn).Return(storagepkg.TxnResult{}, storagepkg.ErrConflict)
+		_, err := server.Txn(context.Background(), req)
+		require.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err := server.Txn(context.Background(), &pb.TxnRequest{Compares: []*pb.Compare{{Key: "key"}}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = server.Txn(context.Background(), &pb.TxnRequest{Success: []*pb.TxnOp{
+			{Op: &pb.TxnOp_Set{Set: &pb.SetRequest{Key: "key", Mode: pb.SetMode_SET_MODE_ADD}}},
+		}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = server.Txn(context.Background(), &pb.TxnRequest{Failure: []*pb.TxnOp{{}}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unsupported storage", func(t *testing.T) {
+		server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+		_, err := server.Txn(context.Background(), req)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
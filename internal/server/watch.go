@@ -0,0 +1,372 @@
+package server
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+const (
+	// watchBufferSize is the number of events a watcher may lag behind before it is dropped.
+	watchBufferSize = 256
+
+	// orderStripes is the number of mutexes that order writes of keys with their events, keys are hashed to them.
+	orderStripes = 256
+)
+
+var (
+	errWatcherLagged = detailedError(codes.ResourceExhausted, "watcher is too slow to receive events",
+		errorInfo(pb.ErrorReason_ERROR_REASON_WATCHER_LAGGED, "", ""))
+	errWatchHubClosed = detailedError(codes.Unavailable, "server is shutting down",
+		errorInfo(pb.ErrorReason_ERROR_REASON_SHUTTING_DOWN, "", ""))
+)
+
+func (s *Server) Watch(req *pb.WatchRequest, stream pb.GRPCStoreService_WatchServer) error {
+	w, err := s.watchers.subscribe(req.GetKey(), req.GetPrefix())
+	if err != nil {
+		return err
+	}
+	defer s.watchers.unsubscribe(w)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-w.done:
+			return w.err
+		case e := <-w.events:
+			err := stream.Send(e)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type watcher struct {
+	key    string
+	prefix bool
+	events chan *pb.WatchEvent
+	done   chan struct{} // closed by the hub when watcher is removed, err holds the reason.
+	err    error
+}
+
+func (w *watcher) matches(key string) bool {
+	if w.prefix {
+		return strings.HasPrefix(key, w.key)
+	}
+
+	return key == w.key
+}
+
+// watchHub delivers change events to watchers. It never blocks publishers: watchers that do not keep up are dropped.
+type watchHub struct {
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+	closed   bool
+
+	order [orderStripes]sync.Mutex // held by writers from a storage call until its events are published.
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+// lockKeys makes writes of keys and publishing of their events happen in the same order for concurrent writers. Stripes
+// are locked in ascending order, so writers of several keys do not deadlock. Keys must not be locked again before
+// calling the returned unlock.
+func (h *watchHub) lockKeys(keys ...string) (unlock func()) {
+	stripes := make([]int, 0, len(keys))
+	seen := make(map[int]bool, len(keys))
+	for _, key := range keys {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(key))
+		i := int(hash.Sum32() % orderStripes)
+		if !seen[i] {
+			seen[i] = true
+			stripes = append(stripes, i)
+		}
+	}
+	sort.Ints(stripes)
+
+	for _, i := range stripes {
+		h.order[i].Lock()
+	}
+
+	return func() {
+		for j := len(stripes) - 1; j >= 0; j-- {
+			h.order[stripes[j]].Unlock()
+		}
+	}
+}
+
+func (h *watchHub) subscribe(key string, prefix bool) (*watcher, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, errWatchHubClosed
+	}
+
+	w := &watcher{
+		key:    key,
+		prefix: prefix,
+		events: make(chan *pb.WatchEvent, watchBufferSize),
+		done:   make(chan struct{}),
+	}
+	h.watchers[w] = struct{}{}
+
+	return w, nil
+}
+
+func (h *watchHub) unsubscribe(w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(w, nil)
+}
+
+// remove must be called with mu locked.
+func (h *watchHub) remove(w *watcher, err error) {
+	if _, ok := h.watchers[w]; !ok {
+		return
+	}
+
+	delete(h.watchers, w)
+	w.err = err
+	close(w.done)
+}
+
+func (h *watchHub) watched(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for w := range h.watchers {
+		if w.matches(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *watchHub) publish(e *pb.WatchEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for w := range h.watchers {
+		if !w.matches(e.GetKey()) {
+			continue
+		}
+
+		select {
+		case w.events <- e:
+		default:
+			h.remove(w, errWatcherLagged)
+		}
+	}
+}
+
+func (h *watchHub) put(key string, value []byte, flags uint32) {
+	h.publish(&pb.WatchEvent{
+		Type:  pb.WatchEvent_TYPE_PUT,
+		Key:   key,
+		Value: value,
+		Flags: flags,
+	})
+}
+
+func (h *watchHub) deleted(key string) {
+	h.publish(&pb.WatchEvent{
+		Type: pb.WatchEvent_TYPE_DELETE,
+		Key:  key,
+	})
+}
+
+func (h *watchHub) expired(key string) {
+	h.publish(&pb.WatchEvent{
+		Type: pb.WatchEvent_TYPE_EXPIRE,
+		Key:  key,
+	})
+}
+
+// close drops all watchers and rejects new ones, so that open streams do not block graceful stop.
+func (h *watchHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for w := range h.watchers {
+		h.remove(w, errWatchHubClosed)
+	}
+}
+
+// observedStorage publishes every successful change made through it to the hub, so watching works with any storage.
+// Keys are locked from the write until its event is published, so events of a key are in the order of its writes.
+type observedStorage struct {
+	IStorage
+	hub *watchHub
+}
+
+func (s *observedStorage) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.Set(key, value, flags, ttl)
+	if err == nil {
+		s.hub.put(key, value, flags)
+	}
+
+	return err
+}
+
+func (s *observedStorage) Add(key string, value []byte, flags uint32, ttl time.Duration) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.Add(key, value, flags, ttl)
+	if err == nil {
+		s.hub.put(key, value, flags)
+	}
+
+	return err
+}
+
+func (s *observedStorage) Replace(key string, value []byte, flags uint32, ttl time.Duration) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.Replace(key, value, flags, ttl)
+	if err == nil {
+		s.hub.put(key, value, flags)
+	}
+
+	return err
+}
+
+func (s *observedStorage) CompareAndSwap(key string, value []byte, flags uint32, version uint64, ttl time.Duration) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.CompareAndSwap(key, value, flags, version, ttl)
+	if err == nil {
+		s.hub.put(key, value, flags)
+	}
+
+	return err
+}
+
+func (s *observedStorage) Append(key string, value []byte) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.Append(key, value)
+	if err == nil {
+		s.putCurrent(key)
+	}
+
+	return err
+}
+
+func (s *observedStorage) Prepend(key string, value []byte) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.Prepend(key, value)
+	if err == nil {
+		s.putCurrent(key)
+	}
+
+	return err
+}
+
+func (s *observedStorage) Increment(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error) {
+	defer s.hub.lockKeys(key)()
+
+	v, err := s.IStorage.Increment(key, delta, initial, ttl)
+	if err == nil {
+		s.putCurrent(key)
+	}
+
+	return v, err
+}
+
+func (s *observedStorage) Decrement(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error) {
+	defer s.hub.lockKeys(key)()
+
+	v, err := s.IStorage.Decrement(key, delta, initial, ttl)
+	if err == nil {
+		s.putCurrent(key)
+	}
+
+	return v, err
+}
+
+func (s *observedStorage) Delete(key string) error {
+	defer s.hub.lockKeys(key)()
+
+	err := s.IStorage.Delete(key)
+	if err == nil {
+		s.hub.deleted(key)
+	}
+
+	return err
+}
+
+func (s *observedStorage) MultiSet(entries []storage.Entry) ([]error, error) {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	defer s.hub.lockKeys(keys...)()
+
+	errs, err := s.IStorage.MultiSet(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err == nil {
+			s.hub.put(entries[i].Key, entries[i].Value, entries[i].Flags)
+		}
+	}
+
+	return errs, nil
+}
+
+func (s *observedStorage) MultiDelete(keys []string) ([]error, error) {
+	defer s.hub.lockKeys(keys...)()
+
+	errs, err := s.IStorage.MultiDelete(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err == nil {
+			s.hub.deleted(keys[i])
+		}
+	}
+
+	return errs, nil
+}
+
+// putCurrent publishes the value of key after a change whose result is computed by the storage. The value is read
+// only when somebody watches the key. The key is still locked, but the value may include later changes made by other
+// clients of the storage.
+func (s *observedStorage) putCurrent(key string) {
+	if !s.hub.watched(key) {
+		return
+	}
+
+	item, err := s.IStorage.Get(key)
+	if err != nil {
+		return
+	}
+
+	s.hub.put(key, item.Value, item.Flags)
+}
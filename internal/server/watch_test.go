@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	servermocks "github.com/IlyaFloppy/grpcstore/internal/server/mocks"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+type watchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.WatchEvent
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(e *pb.WatchEvent) error {
+	s.events <- e
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctrl := gomock.NewController(t)
+
+	storage := servermocks.NewMockIStorage(ctrl)
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchStream{
+		ctx:    ctx,
+		events: make(chan *pb.WatchEvent, 10),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Watch(&pb.WatchRequest{Key: "user/", Prefix: true}, stream)
+	}()
+	require.Eventually(t, func() bool {
+		return server.watchers.watched("user/1")
+	}, time.Second, time.Millisecond)
+
+	storage.EXPECT().Set("user/1", []byte("12345"), uint32(7), time.Duration(0)).Return(nil)
+	_, err := server.Set(context.Background(), &pb.SetRequest{Key: "user/1", Value: []byte("12345"), Flags: 7})
+	require.NoError(t, err)
+
+	storage.EXPECT().Delete("other").Return(nil)
+	_, err = server.Delete(context.Background(), &pb.DeleteRequest{Key: "other"})
+	require.NoError(t, err)
+
+	storage.EXPECT().Increment("user/2", uint64(1), nil, time.Duration(0)).Return(uint64(2), nil)
+	storage.EXPECT().Get("user/2").Return(storagepkg.Item{Value: []byte("2")}, nil)
+	_, err = server.Increment(context.Background(), &pb.IncrementRequest{Key: "user/2", Delta: 1})
+	require.NoError(t, err)
+
+	storage.EXPECT().Delete("user/1").Return(nil)
+	_, err = server.Delete(context.Background(), &pb.DeleteRequest{Key: "user/1"})
+	require.NoError(t, err)
+
+	server.watchers.expired("user/2")
+
+	require.Equal(t, &pb.WatchEvent{Type: pb.WatchEvent_TYPE_PUT, Key: "user/1", Value: []byte("12345"), Flags: 7}, <-stream.events)
+	require.Equal(t, &pb.WatchEvent{Type: pb.WatchEvent_TYPE_PUT, Key: "user/2", Value: []byte("2")}, <-stream.events)
+	require.Equal(t, &pb.WatchEvent{Type: pb.WatchEvent_TYPE_DELETE, Key: "user/1"}, <-stream.events)
+	require.Equal(t, &pb.WatchEvent{Type: pb.WatchEvent_TYPE_EXPIRE, Key: "user/2"}, <-stream.events)
+
+	cancel()
+	require.Equal(t, codes.Canceled, status.Code(<-errCh))
+}
+
+// appliedStorage records values in the order they are applied and returns after a random delay.
+type appliedStorage struct {
+	IStorage
+	mu      sync.Mutex
+	applied [][]byte
+}
+
+func (s *appliedStorage) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
+	s.mu.Lock()
+	s.applied = append(s.applied, value)
+	s.mu.Unlock()
+
+	time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond) //nolint:gosec
+	return nil
+}
+
+func TestWatchOrder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	storage := &appliedStorage{}
+	server := New(zerolog.New(os.Stderr), config.ServerConfig{}, storage, nil)
+
+	const writes = 50
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchStream{
+		ctx:    ctx,
+		events: make(chan *pb.WatchEvent, writes),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Watch(&pb.WatchRequest{Key: "key"}, stream)
+	}()
+	require.Eventually(t, func() bool {
+		return server.watchers.watched("key")
+	}, time.Second, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := server.Set(context.Background(), &pb.SetRequest{Key: "key", Value: []byte(strconv.Itoa(i))})
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < writes; i++ {
+		require.Equal(t, storage.applied[i], (<-stream.events).GetValue())
+	}
+
+	cancel()
+	require.Equal(t, codes.Canceled, status.Code(<-errCh))
+}
+
+func TestWatchHub(t *testing.T) {
+	t.Run("slow watcher is dropped", func(t *testing.T) {
+		hub := newWatchHub()
+		w, err := hub.subscribe("key", false)
+		require.NoError(t, err)
+
+		for i := 0; i <= watchBufferSize; i++ {
+			hub.deleted("key")
+		}
+
+		<-w.done
+		require.Equal(t, errWatcherLagged, w.err)
+		require.False(t, hub.watched("key"))
+	})
+
+	t.Run("lock keys", func(t *testing.T) {
+		hub := newWatchHub()
+		unlock := hub.lockKeys("a", "b", "a") // repeated keys and keys sharing a stripe are locked once.
+		locked := make(chan struct{})
+		go func() {
+			defer hub.lockKeys("b")()
+			close(locked)
+		}()
+
+		select {
+		case <-locked:
+			t.Fatal("key is locked twice")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		unlock()
+		<-locked
+	})
+
+	t.Run("close", func(t *testing.T) {
+		hub := newWatchHub()
+		w, err := hub.subscribe("key", false)
+		require.NoError(t, err)
+
+		hub.close()
+		<-w.done
+		require.Equal(t, errWatchHubClosed, w.err)
+
+		_, err = hub.subscribe("key", false)
+		require.Equal(t, errWatchHubClosed, err)
+	})
+}
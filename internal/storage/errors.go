@@ -2,4 +2,96 @@ package storage
 
 import "errors"
 
-var ErrNotFound = errors.New("key not found")
+// Errors returned by storages. Server tells them apart by marker methods rather than by identity, so clients such as
+// sdk/memcached declare errors with the same markers without depending on this package, and backends translate them
+// with Translate to make errors.Is work as well.
+var (
+	ErrNotFound    = notFoundError{errors.New("key not found")}
+	ErrNotStored   = notStoredError{errors.New("not stored")}
+	ErrConflict    = conflictError{errors.New("version mismatch")}
+	ErrNonNumeric  = nonNumericError{errors.New("cannot increment or decrement non-numeric value")}
+	ErrTooLarge    = tooLargeError{errors.New("value is too large")}
+	ErrUnavailable = unavailableError{errors.New("storage is unavailable")}
+	ErrTimeout     = timeoutError{errors.New("storage timed out")}
+	ErrInvalidKey  = invalidKeyError{errors.New("invalid key")}
+	ErrChunked     = chunkedError{errors.New("value is chunked, use GetStream")}
+)
+
+type notFoundError struct{ error }
+type notStoredError struct{ error }
+type conflictError struct{ error }
+type nonNumericError struct{ error }
+type tooLargeError struct{ error }
+type unavailableError struct{ error }
+type timeoutError struct{ error }
+type invalidKeyError struct{ error }
+type chunkedError struct{ error }
+
+func (notFoundError) NotFoundErrorMarker()       {}
+func (notStoredError) NotStoredErrorMarker()     {}
+func (conflictError) ConflictErrorMarker()       {}
+func (nonNumericError) NonNumericErrorMarker()   {}
+func (tooLargeError) TooLargeErrorMarker()       {}
+func (unavailableError) UnavailableErrorMarker() {}
+func (timeoutError) TimeoutErrorMarker()         {}
+func (invalidKeyError) InvalidKeyErrorMarker()   {}
+func (chunkedError) ChunkedErrorMarker()         {}
+
+// Translate returns err so that errors.Is matches the storage error of the same kind, the kind is detected by marker
+// methods of err or of errors it wraps. Message and wrapped errors of err are kept. Nil and errors without markers are
+// returned as is.
+func Translate(err error) error {
+	kind := errorKind(err)
+	if kind == nil {
+		return err
+	}
+
+	return &translatedError{
+		cause: err,
+		kind:  kind,
+	}
+}
+
+func errorKind(err error) error {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch err.(type) {
+		case interface{ NotFoundErrorMarker() }:
+			return ErrNotFound
+		case interface{ NotStoredErrorMarker() }:
+			return ErrNotStored
+		case interface{ ConflictErrorMarker() }:
+			return ErrConflict
+		case interface{ NonNumericErrorMarker() }:
+			return ErrNonNumeric
+		case interface{ TooLargeErrorMarker() }:
+			return ErrTooLarge
+		case interface{ UnavailableErrorMarker() }:
+			return ErrUnavailable
+		case interface{ TimeoutErrorMarker() }:
+			return ErrTimeout
+		case interface{ InvalidKeyErrorMarker() }:
+			return ErrInvalidKey
+		case interface{ ChunkedErrorMarker() }:
+			return ErrChunked
+		}
+	}
+
+	return nil
+}
+
+type translatedError struct {
+	cause error
+	kind  error
+}
+
+func (e *translatedError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *translatedError) Unwrap() error {
+	return e.cause
+}
+
+func (e *translatedError) Is(target error) bool {
+	return target == e.kind
+}
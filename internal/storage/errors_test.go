@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutMarked struct{ error }
+
+func (timeoutMarked) TimeoutErrorMarker() {}
+
+func TestTranslate(t *testing.T) {
+	require.NoError(t, Translate(nil))
+
+	plain := errors.New("failed on purpose")
+	require.Equal(t, plain, Translate(plain))
+
+	cause := timeoutMarked{errors.New("i/o timeout")}
+	err := Translate(fmt.Errorf("failed to read: %w", cause))
+	require.ErrorIs(t, err, ErrTimeout)
+	require.NotErrorIs(t, err, ErrUnavailable)
+	require.ErrorIs(t, err, cause)
+	require.Equal(t, "failed to read: i/o timeout", err.Error())
+
+	require.ErrorIs(t, Translate(ErrConflict), ErrConflict)
+}
@@ -1,23 +1,398 @@
 package inmemory
 
-import "github.com/IlyaFloppy/grpcstore/internal/storage"
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-func (s *Storage) Get(key string) ([]byte, error) {
-	if val, ok := s.hm[key]; ok {
-		return val, nil
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+)
+
+func (s *Storage) Get(key string) (storage.Item, error) {
+	if !storage.ValidKey(key) {
+		return storage.Item{}, storage.ErrInvalidKey
+	}
+
+	now := time.Now()
+
+	s.mu.RLock()
+	e, ok := s.hm[key]
+	s.mu.RUnlock()
+
+	if !ok {
+		return storage.Item{}, storage.ErrNotFound
+	}
+
+	if e.expired(now) {
+		var removed bool
+
+		s.mu.Lock()
+		if e, ok := s.hm[key]; ok && e.expired(now) { // entry could have been overwritten after RUnlock.
+			s.remove(key)
+			removed = true
+		}
+		onExpire := s.onExpire
+		s.mu.Unlock()
+
+		if removed && onExpire != nil {
+			onExpire(key)
+		}
+
+		return storage.Item{}, storage.ErrNotFound
+	}
+
+	return e.item(), nil
+}
+
+func (s *Storage) Stat(key string) (storage.Meta, error) {
+	if !storage.ValidKey(key) {
+		return storage.Meta{}, storage.ErrInvalidKey
+	}
+
+	now := time.Now()
+
+	s.mu.RLock()
+	e, ok := s.hm[key]
+	s.mu.RUnlock()
+
+	if !ok || e.expired(now) { // expired entries are left for Get and the sweeper.
+		return storage.Meta{}, storage.ErrNotFound
+	}
+
+	meta := storage.Meta{
+		Size:       uint64(len(e.value)),
+		Version:    e.version,
+		Flags:      e.flags,
+		CreatedAt:  e.createdAt,
+		ModifiedAt: e.modifiedAt,
+	}
+	if !e.expiresAt.IsZero() {
+		meta.TTL = e.expiresAt.Sub(now)
+	}
+
+	return meta, nil
+}
+
+func (s *Storage) GetAndTouch(key string, ttl time.Duration) (storage.Item, error) {
+	if !storage.ValidKey(key) {
+		return storage.Item{}, storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, err := s.touch(key, ttl)
+	if err != nil {
+		return storage.Item{}, err
+	}
+
+	return e.item(), nil
+}
+
+func (s *Storage) Touch(key string, ttl time.Duration) error {
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.touch(key, ttl)
+
+	return err
+}
+
+// touch must be called with mu locked. The version is kept as the value does not change.
+func (s *Storage) touch(key string, ttl time.Duration) (entry, error) {
+	now := time.Now()
+
+	e, ok := s.hm[key]
+	if !ok || e.expired(now) {
+		return entry{}, storage.ErrNotFound
+	}
+
+	e.expiresAt = time.Time{}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+	s.put(key, e)
+
+	return e, nil
+}
+
+func (s *Storage) MultiGet(keys []string) (map[string]storage.Item, error) {
+	for _, key := range keys {
+		if !storage.ValidKey(key) {
+			return nil, storage.ErrInvalidKey
+		}
+	}
+
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make(map[string]storage.Item, len(keys))
+	for _, key := range keys {
+		e, ok := s.hm[key]
+		if !ok || e.expired(now) { // expired entries are left for the sweeper to avoid taking the write lock.
+			continue
+		}
+
+		items[key] = e.item()
+	}
+
+	return items, nil
+}
+
+func (s *Storage) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
 	}
 
-	return nil, storage.ErrNotFound
+	s.mu.Lock()
+	s.put(key, s.newEntry(value, flags, ttl))
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Storage) Add(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.hm[key]; ok && !e.expired(time.Now()) {
+		return storage.ErrNotStored
+	}
+
+	s.put(key, s.newEntry(value, flags, ttl))
+
+	return nil
 }
 
-func (s *Storage) Set(key string, value []byte) error {
-	s.hm[key] = value
+func (s *Storage) Replace(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.hm[key]; !ok || e.expired(time.Now()) {
+		return storage.ErrNotStored
+	}
+
+	s.put(key, s.newEntry(value, flags, ttl))
 
 	return nil
 }
 
+func (s *Storage) Append(key string, value []byte) error {
+	return s.concat(key, func(old []byte) []byte {
+		return append(append(make([]byte, 0, len(old)+len(value)), old...), value...)
+	})
+}
+
+func (s *Storage) Prepend(key string, value []byte) error {
+	return s.concat(key, func(old []byte) []byte {
+		return append(append(make([]byte, 0, len(old)+len(value)), value...), old...)
+	})
+}
+
+// concat replaces value of an existing key with a new slice, old value is never modified because it could have been
+// returned to a reader.
+func (s *Storage) concat(key string, join func(old []byte) []byte) error {
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.hm[key]
+	if !ok || e.expired(time.Now()) {
+		return storage.ErrNotStored
+	}
+
+	updated := s.newEntry(join(e.value), e.flags, 0)
+	updated.expiresAt = e.expiresAt
+	s.put(key, updated)
+
+	return nil
+}
+
+func (s *Storage) CompareAndSwap(key string, value []byte, flags uint32, version uint64, ttl time.Duration) error {
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.hm[key]
+	if !ok || e.expired(time.Now()) {
+		return storage.ErrNotFound
+	}
+
+	if e.version != version {
+		return storage.ErrConflict
+	}
+
+	s.put(key, s.newEntry(value, flags, ttl))
+
+	return nil
+}
+
+// Delete fails with storage.ErrNotFound if the key is missing, like in memcached.
 func (s *Storage) Delete(key string) error {
-	delete(s.hm, key)
+	if !storage.ValidKey(key) {
+		return storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.delete(key, time.Now())
+}
+
+// delete must be called with mu locked. Expired entry is removed too, but the key is reported missing.
+func (s *Storage) delete(key string, now time.Time) error {
+	e, ok := s.hm[key]
+	s.remove(key)
+
+	if !ok || e.expired(now) {
+		return storage.ErrNotFound
+	}
 
 	return nil
 }
+
+func (s *Storage) MultiSet(entries []storage.Entry) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	errs := make([]error, len(entries))
+	for i, e := range entries {
+		if !storage.ValidKey(e.Key) {
+			errs[i] = storage.ErrInvalidKey
+			continue
+		}
+
+		s.put(e.Key, s.newEntry(e.Value, e.Flags, e.TTL))
+	}
+
+	return errs, nil
+}
+
+func (s *Storage) MultiDelete(keys []string) ([]error, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		if !storage.ValidKey(key) {
+			errs[i] = storage.ErrInvalidKey
+			continue
+		}
+
+		errs[i] = s.delete(key, now)
+	}
+
+	return errs, nil
+}
+
+func (s *Storage) Increment(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error) {
+	return s.arithmetic(key, initial, ttl, func(v uint64) uint64 {
+		return v + delta
+	})
+}
+
+func (s *Storage) Decrement(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error) {
+	return s.arithmetic(key, initial, ttl, func(v uint64) uint64 {
+		if v < delta {
+			return 0
+		}
+
+		return v - delta
+	})
+}
+
+func (s *Storage) arithmetic(key string, initial *uint64, ttl time.Duration, op func(v uint64) uint64) (uint64, error) {
+	if !storage.ValidKey(key) {
+		return 0, storage.ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.hm[key]
+	if !ok || e.expired(time.Now()) {
+		if initial == nil {
+			return 0, storage.ErrNotFound
+		}
+
+		s.put(key, s.newEntry([]byte(strconv.FormatUint(*initial, 10)), 0, ttl))
+
+		return *initial, nil
+	}
+
+	v, err := strconv.ParseUint(string(e.value), 10, 64)
+	if err != nil {
+		return 0, storage.ErrNonNumeric
+	}
+
+	v = op(v)
+
+	updated := s.newEntry([]byte(strconv.FormatUint(v, 10)), e.flags, 0)
+	updated.expiresAt = e.expiresAt // counters keep their expiration like in memcached.
+	s.put(key, updated)
+
+	return v, nil
+}
+
+// List returns up to limit items with keys that start with prefix and are greater than after, in key order.
+func (s *Storage) List(prefix, after string, limit int, withValues bool) ([]storage.KeyItem, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := sort.SearchStrings(s.keys, prefix)
+	if after > prefix {
+		i = sort.Search(len(s.keys), func(i int) bool {
+			return s.keys[i] > after
+		})
+	}
+
+	var items []storage.KeyItem
+	for ; i < len(s.keys) && len(items) < limit; i++ {
+		key := s.keys[i]
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+
+		e := s.hm[key]
+		if e.expired(now) {
+			continue
+		}
+
+		item := e.item()
+		if !withValues {
+			item.Value = nil
+		}
+
+		items = append(items, storage.KeyItem{
+			Key:  key,
+			Item: item,
+		})
+	}
+
+	return items, nil
+}
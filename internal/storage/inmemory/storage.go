@@ -2,17 +2,58 @@ package inmemory
 
 import (
 	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
 )
 
+const defaultSweepInterval = time.Minute
+
+type entry struct {
+	value      []byte
+	version    uint64
+	flags      uint32
+	expiresAt  time.Time // zero value means that entry never expires.
+	createdAt  time.Time // kept while the key is overwritten, reset when it is deleted or expires.
+	modifiedAt time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func (e entry) item() storage.Item {
+	return storage.Item{
+		Value:   e.value,
+		Version: e.version,
+		Flags:   e.flags,
+	}
+}
+
 type Storage struct {
+	cfg     config.InMemoryStorageConfig
 	readyCh chan struct{}
-	hm      map[string][]byte
+
+	mu       sync.RWMutex
+	hm       map[string]entry
+	keys     []string // sorted keys of hm for listing.
+	size     uint64   // total size of values in hm.
+	version  uint64   // last assigned entry version, it is never reused so deleting and recreating a key changes its version.
+	onExpire func(key string)
 }
 
-func New() *Storage {
+func New(cfg config.InMemoryStorageConfig) *Storage {
+	if cfg.SweepInterval <= 0 {
+		cfg.SweepInterval = defaultSweepInterval
+	}
+
 	return &Storage{
+		cfg:     cfg,
 		readyCh: make(chan struct{}),
-		hm:      make(map[string][]byte),
+		hm:      make(map[string]entry),
 	}
 }
 
@@ -21,12 +62,116 @@ func (s *Storage) Name() string {
 }
 
 func (s *Storage) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.SweepInterval)
+	defer ticker.Stop()
+
 	close(s.readyCh)
 
-	<-ctx.Done()
-	return nil
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			s.sweep(now)
+		}
+	}
 }
 
 func (s *Storage) ReadyCh() <-chan struct{} {
 	return s.readyCh
 }
+
+// Stats counts expired keys that were not removed yet.
+func (s *Storage) Stats() (storage.Stats, error) {
+	s.mu.RLock()
+	keys, size := len(s.hm), s.size
+	s.mu.RUnlock()
+
+	return storage.Stats{
+		Keys:  uint64(keys),
+		Bytes: size,
+	}, nil
+}
+
+// OnExpire registers fn to be called for every key removed because of expiration.
+func (s *Storage) OnExpire(fn func(key string)) {
+	s.mu.Lock()
+	s.onExpire = fn
+	s.mu.Unlock()
+}
+
+// newEntry must be called with mu locked.
+func (s *Storage) newEntry(value []byte, flags uint32, ttl time.Duration) entry {
+	s.version++
+
+	now := time.Now()
+	e := entry{
+		value:      value,
+		version:    s.version,
+		flags:      flags,
+		createdAt:  now,
+		modifiedAt: now,
+	}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+
+	return e
+}
+
+// put must be called with mu locked.
+func (s *Storage) put(key string, e entry) {
+	old, ok := s.hm[key]
+	if !ok {
+		i := sort.SearchStrings(s.keys, key)
+		s.keys = append(s.keys, "")
+		copy(s.keys[i+1:], s.keys[i:])
+		s.keys[i] = key
+	} else if !old.expired(e.modifiedAt) {
+		e.createdAt = old.createdAt
+	}
+
+	s.size -= uint64(len(old.value))
+	s.size += uint64(len(e.value))
+	s.hm[key] = e
+}
+
+// remove must be called with mu locked.
+func (s *Storage) remove(key string) {
+	e, ok := s.hm[key]
+	if !ok {
+		return
+	}
+
+	s.size -= uint64(len(e.value))
+	delete(s.hm, key)
+
+	i := sort.SearchStrings(s.keys, key)
+	s.keys = append(s.keys[:i], s.keys[i+1:]...)
+}
+
+// sweep removes all expired entries so that keys which are never read again do not leak memory.
+func (s *Storage) sweep(now time.Time) {
+	var expired []string
+
+	s.mu.Lock()
+	keys := s.keys[:0] // filter in place instead of calling remove to avoid shifting the index for every key.
+	for _, key := range s.keys {
+		if e := s.hm[key]; e.expired(now) {
+			s.size -= uint64(len(e.value))
+			delete(s.hm, key)
+			expired = append(expired, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	s.keys = keys
+	onExpire := s.onExpire
+	s.mu.Unlock()
+
+	if onExpire != nil {
+		for _, key := range expired {
+			onExpire(key)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package inmemory
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+)
+
+func TestExpiration(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := New(config.InMemoryStorageConfig{})
+
+	err := s.Set("key", []byte("12345"), 0, time.Hour)
+	require.NoError(t, err)
+	err = s.Set("short", []byte("12345"), 0, time.Millisecond)
+	require.NoError(t, err)
+	err = s.Set("forever", []byte("12345"), 0, 0)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	item, err := s.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("12345"), item.Value)
+
+	_, err = s.Get("short")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	require.NotContains(t, s.hm, "short") // removed lazily on get.
+
+	var expired []string
+	s.OnExpire(func(key string) {
+		expired = append(expired, key)
+	})
+
+	s.sweep(time.Now().Add(2 * time.Hour))
+	require.NotContains(t, s.hm, "key")
+	require.Equal(t, []string{"key"}, expired)
+	require.Contains(t, s.hm, "forever")
+}
+
+func TestSweeper(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := New(config.InMemoryStorageConfig{SweepInterval: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Run(ctx) }()
+	<-s.ReadyCh()
+
+	err := s.Set("key", []byte("12345"), 0, time.Millisecond)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		_, ok := s.hm["key"]
+		return !ok
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	err := s.CompareAndSwap("key", []byte("12345"), 0, 1, 0)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = s.Set("key", []byte("12345"), 0, 0)
+	require.NoError(t, err)
+
+	item, err := s.Get("key")
+	require.NoError(t, err)
+
+	err = s.CompareAndSwap("key", []byte("54321"), 0, item.Version, 0)
+	require.NoError(t, err)
+
+	err = s.CompareAndSwap("key", []byte("00000"), 0, item.Version, 0)
+	require.ErrorIs(t, err, storage.ErrConflict)
+
+	updated, err := s.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("54321"), updated.Value)
+	require.NotEqual(t, item.Version, updated.Version)
+
+	err = s.Delete("key")
+	require.NoError(t, err)
+	err = s.Set("key", []byte("12345"), 0, 0)
+	require.NoError(t, err)
+
+	recreated, err := s.Get("key")
+	require.NoError(t, err)
+	require.NotEqual(t, item.Version, recreated.Version)
+}
+
+func TestAddReplace(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	err := s.Replace("key", []byte("12345"), 0, 0)
+	require.ErrorIs(t, err, storage.ErrNotStored)
+
+	err = s.Add("key", []byte("12345"), 0, 0)
+	require.NoError(t, err)
+
+	err = s.Add("key", []byte("54321"), 0, 0)
+	require.ErrorIs(t, err, storage.ErrNotStored)
+
+	err = s.Replace("key", []byte("54321"), 0, 0)
+	require.NoError(t, err)
+
+	item, err := s.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("54321"), item.Value)
+}
+
+func TestDelete(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	require.ErrorIs(t, s.Delete("key"), storage.ErrNotFound)
+
+	require.NoError(t, s.Set("key", []byte("12345"), 0, 0))
+	require.NoError(t, s.Set("expired", []byte("12345"), 0, time.Nanosecond))
+	time.Sleep(time.Millisecond)
+
+	require.NoError(t, s.Delete("key"))
+	require.ErrorIs(t, s.Delete("key"), storage.ErrNotFound)
+
+	require.NoError(t, s.Set("key", []byte("12345"), 0, 0))
+	errs, err := s.MultiDelete([]string{"key", "missing", "expired"})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], storage.ErrNotFound)
+	require.ErrorIs(t, errs[2], storage.ErrNotFound)
+}
+
+func TestInvalidKey(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	for _, key := range []string{"", "with space", "with\nnewline", "with\x7fdel", strings.Repeat("k", storage.MaxKeySize+1)} {
+		require.ErrorIs(t, s.Set(key, []byte("12345"), 0, 0), storage.ErrInvalidKey, key)
+		_, err := s.Get(key)
+		require.ErrorIs(t, err, storage.ErrInvalidKey, key)
+		require.ErrorIs(t, s.Delete(key), storage.ErrInvalidKey, key)
+		_, err = s.MultiGet([]string{"key", key})
+		require.ErrorIs(t, err, storage.ErrInvalidKey, key)
+		_, err = s.Txn(storage.Txn{Success: []storage.Op{{Type: storage.OpGet, Key: key}}})
+		require.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+
+	errs, err := s.MultiSet([]storage.Entry{{Key: "key"}, {Key: ""}})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], storage.ErrInvalidKey)
+
+	require.NoError(t, s.Set(strings.Repeat("k", storage.MaxKeySize), []byte("12345"), 0, 0))
+}
+
+func TestArithmetic(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	_, err := s.Increment("counter", 1, nil, 0)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	initial := uint64(10)
+	v, err := s.Increment("counter", 1, &initial, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), v)
+
+	v, err = s.Increment("counter", 5, &initial, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), v)
+	require.False(t, s.hm["counter"].expiresAt.IsZero())
+
+	v, err = s.Decrement("counter", 100, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), v)
+
+	err = s.Set("text", []byte("abc"), 0, 0)
+	require.NoError(t, err)
+	_, err = s.Increment("text", 1, nil, 0)
+	require.ErrorIs(t, err, storage.ErrNonNumeric)
+}
+
+func TestAppendPrepend(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	err := s.Append("key", []byte("c"))
+	require.ErrorIs(t, err, storage.ErrNotStored)
+
+	err = s.Set("key", []byte("b"), 7, 0)
+	require.NoError(t, err)
+	before, err := s.Get("key")
+	require.NoError(t, err)
+
+	err = s.Append("key", []byte("c"))
+	require.NoError(t, err)
+	err = s.Prepend("key", []byte("a"))
+	require.NoError(t, err)
+
+	item, err := s.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("abc"), item.Value)
+	require.Equal(t, uint32(7), item.Flags)
+	require.Equal(t, []byte("b"), before.Value)
+}
+
+func TestTouch(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	err := s.Touch("key", time.Hour)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = s.Set("key", []byte("12345"), 0, time.Millisecond)
+	require.NoError(t, err)
+
+	item, err := s.GetAndTouch("key", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []byte("12345"), item.Value)
+
+	time.Sleep(5 * time.Millisecond)
+
+	err = s.Touch("key", 0)
+	require.NoError(t, err)
+	require.True(t, s.hm["key"].expiresAt.IsZero())
+}
+
+func TestStat(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	_, err := s.Stat("key")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = s.Set("key", []byte("12345"), 7, time.Hour)
+	require.NoError(t, err)
+
+	created, err := s.Stat("key")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), created.Size)
+	require.Equal(t, uint32(7), created.Flags)
+	require.InDelta(t, time.Hour, created.TTL, float64(time.Second))
+	require.Equal(t, created.CreatedAt, created.ModifiedAt)
+
+	time.Sleep(time.Millisecond)
+
+	err = s.Append("key", []byte("678"))
+	require.NoError(t, err)
+
+	modified, err := s.Stat("key")
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), modified.Size)
+	require.Greater(t, modified.Version, created.Version)
+	require.Equal(t, created.CreatedAt, modified.CreatedAt)
+	require.True(t, modified.ModifiedAt.After(created.ModifiedAt))
+
+	err = s.Touch("key", 0)
+	require.NoError(t, err)
+
+	touched, err := s.Stat("key")
+	require.NoError(t, err)
+	require.Zero(t, touched.TTL)
+	require.Equal(t, modified.ModifiedAt, touched.ModifiedAt)
+
+	err = s.Delete("key")
+	require.NoError(t, err)
+	err = s.Set("key", []byte("12345"), 0, 0)
+	require.NoError(t, err)
+
+	recreated, err := s.Stat("key")
+	require.NoError(t, err)
+	require.True(t, recreated.CreatedAt.After(created.CreatedAt))
+}
+
+func TestStats(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	require.NoError(t, s.Set("a", []byte("12345"), 0, 0))
+	require.NoError(t, s.Set("b", []byte("123"), 0, time.Hour))
+	require.NoError(t, s.Set("short", []byte("1"), 0, time.Millisecond))
+	require.NoError(t, s.Append("b", []byte("45")))
+	require.NoError(t, s.Set("a", []byte("1"), 0, 0))
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := s.Get("a")
+	require.NoError(t, err)
+	_, err = s.Get("missing")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.GetAndTouch("b", 0)
+	require.NoError(t, err)
+	_, err = s.MultiGet([]string{"a", "b", "short"})
+	require.NoError(t, err)
+
+	stats, err := s.Stats()
+	require.NoError(t, err)
+	require.Equal(t, storage.Stats{Keys: 3, Bytes: 7}, stats)
+
+	s.sweep(time.Now())
+	require.NoError(t, s.Delete("a"))
+
+	stats, err = s.Stats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stats.Keys)
+	require.Equal(t, uint64(5), stats.Bytes)
+}
+
+func TestList(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	for _, key := range []string{"user/3", "user/1", "other", "user/2", "user/4"} {
+		err := s.Set(key, []byte(key), 0, 0)
+		require.NoError(t, err)
+	}
+	err := s.Delete("user/4")
+	require.NoError(t, err)
+	require.Equal(t, []string{"other", "user/1", "user/2", "user/3"}, s.keys)
+
+	items, err := s.List("user/", "", 2, false)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "user/1", items[0].Key)
+	require.Nil(t, items[0].Value)
+	require.Equal(t, "user/2", items[1].Key)
+
+	items, err = s.List("user/", "user/2", 2, true)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "user/3", items[0].Key)
+	require.Equal(t, []byte("user/3"), items[0].Value)
+
+	err = s.Set("user/0", []byte("0"), 0, time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	items, err = s.List("", "", 10, false)
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+
+	s.sweep(time.Now())
+	require.Equal(t, []string{"other", "user/1", "user/2", "user/3"}, s.keys)
+}
+
+func TestTxn(t *testing.T) {
+	s := New(config.InMemoryStorageConfig{})
+
+	require.NoError(t, s.Set("from", []byte("item"), 0, 0))
+	from, err := s.Get("from")
+	require.NoError(t, err)
+
+	move := storage.Txn{
+		Compares: []storage.Compare{
+			{Key: "from", Target: storage.CompareVersion, Version: from.Version},
+			{Key: "to", Target: storage.CompareExists, Exists: false},
+		},
+		Success: []storage.Op{
+			{Type: storage.OpDelete, Key: "from"},
+			{Type: storage.OpSet, Key: "to", Value: []byte("item"), Flags: 3},
+			{Type: storage.OpGet, Key: "to"},
+		},
+		Failure: []storage.Op{
+			{Type: storage.OpGet, Key: "from"},
+		},
+	}
+
+	res, err := s.Txn(move)
+	require.NoError(t, err)
+	require.True(t, res.Succeeded)
+	require.True(t, res.Atomic)
+	require.Len(t, res.Results, 3)
+	require.True(t, res.Results[2].Found)
+	require.Equal(t, []byte("item"), res.Results[2].Item.Value)
+	require.Equal(t, uint32(3), res.Results[2].Item.Flags)
+
+	_, err = s.Get("from")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	res, err = s.Txn(move)
+	require.NoError(t, err)
+	require.False(t, res.Succeeded)
+	require.Equal(t, []storage.OpResult{{}}, res.Results)
+
+	res, err = s.Txn(storage.Txn{
+		Compares: []storage.Compare{{Key: "to", Target: storage.CompareValue, Value: []byte("item")}},
+	})
+	require.NoError(t, err)
+	require.True(t, res.Succeeded)
+	require.Empty(t, res.Results)
+}
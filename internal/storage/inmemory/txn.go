@@ -0,0 +1,67 @@
+package inmemory
+
+import (
+	"time"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+)
+
+// Txn is atomic as compares and ops are executed under a single write lock.
+func (s *Storage) Txn(txn storage.Txn) (storage.TxnResult, error) {
+	for _, c := range txn.Compares {
+		if !storage.ValidKey(c.Key) {
+			return storage.TxnResult{}, storage.ErrInvalidKey
+		}
+	}
+	for _, ops := range [][]storage.Op{txn.Success, txn.Failure} {
+		for _, op := range ops {
+			if !storage.ValidKey(op.Key) {
+				return storage.TxnResult{}, storage.ErrInvalidKey
+			}
+		}
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := storage.TxnResult{
+		Succeeded: true,
+		Atomic:    true,
+	}
+
+	for _, c := range txn.Compares {
+		var item *storage.Item
+		if e, ok := s.hm[c.Key]; ok && !e.expired(now) {
+			it := e.item()
+			item = &it
+		}
+
+		if !c.Holds(item) {
+			res.Succeeded = false
+			break
+		}
+	}
+
+	ops := txn.Success
+	if !res.Succeeded {
+		ops = txn.Failure
+	}
+
+	res.Results = make([]storage.OpResult, len(ops))
+	for i, op := range ops {
+		switch op.Type {
+		case storage.OpGet:
+			if e, ok := s.hm[op.Key]; ok && !e.expired(now) {
+				res.Results[i] = storage.OpResult{Item: e.item(), Found: true}
+			}
+		case storage.OpSet:
+			s.put(op.Key, s.newEntry(op.Value, op.Flags, op.TTL))
+		case storage.OpDelete:
+			s.remove(op.Key)
+		}
+	}
+
+	return res, nil
+}
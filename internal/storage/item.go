@@ -0,0 +1,58 @@
+package storage
+
+import "time"
+
+// MaxKeySize is the size limit of keys in bytes.
+const MaxKeySize = 250
+
+// ValidKey reports whether key is accepted by storages. The rule is the one of memcached text protocol, so that every
+// backend accepts the same keys: 1 to MaxKeySize bytes without spaces and control characters.
+func ValidKey(key string) bool {
+	if len(key) == 0 || len(key) > MaxKeySize {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
+type Item struct {
+	Value   []byte
+	Version uint64 // opaque token that changes on every write of the key.
+	Flags   uint32 // opaque client data stored along with the value.
+}
+
+type KeyItem struct {
+	Key string
+	Item
+}
+
+// Meta describes an item without its value. Zero times mean that the storage does not track them.
+type Meta struct {
+	Size       uint64
+	Version    uint64
+	Flags      uint32
+	TTL        time.Duration // remaining ttl, zero if the item never expires.
+	CreatedAt  time.Time
+	ModifiedAt time.Time
+}
+
+// Entry is a single write of a batch.
+type Entry struct {
+	Key   string
+	Value []byte
+	Flags uint32
+	TTL   time.Duration
+}
+
+// Stats describe contents of a storage.
+type Stats struct {
+	Keys  uint64
+	Bytes uint64            // total size of values.
+	Raw   map[string]string // backend specific statistics as reported by the backend, nil if there are none.
+}
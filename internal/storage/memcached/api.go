@@ -1,30 +1,308 @@
 package memcached
 
-import "github.com/pkg/errors"
+import (
+	"strconv"
+	"time"
 
-func (s *Storage) Get(key string) ([]byte, error) {
-	res, err := s.client.Get(key)
+	"github.com/pkg/errors"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+// Get and other plain commands fail with storage.ErrChunked on values written with PutStream, they are checked only
+// when the plain key is missing. MultiGet reports such keys missing.
+func (s *Storage) Get(key string) (storage.Item, error) {
+	res, err := s.client.Gets(key)
+	if errors.Is(err, memcached.ErrNotFound) {
+		err = s.missed(key, err)
+	}
+	if err != nil {
+		return storage.Item{}, wrap(err, "failed to get key")
+	}
+
+	return storage.Item{
+		Value:   res.Value,
+		Version: res.CAS,
+		Flags:   res.Flags,
+	}, nil
+}
+
+// Stat uses meta get command which requires memcached 1.6 or newer. Memcached does not track creation and modification
+// time of items. Values written with PutStream are described by their manifest.
+func (s *Storage) Stat(key string) (storage.Meta, error) {
+	meta, err := s.client.MetaGet(key)
+	if errors.Is(err, memcached.ErrNotFound) {
+		return s.statChunked(key)
+	}
+	if err != nil {
+		return storage.Meta{}, wrap(err, "failed to stat key")
+	}
+
+	return storage.Meta{
+		Size:    uint64(meta.Size),
+		Version: meta.CAS,
+		Flags:   meta.Flags,
+		TTL:     remainingTTL(meta.TTL),
+	}, nil
+}
+
+func (s *Storage) statChunked(key string) (storage.Meta, error) {
+	res, err := s.client.Gets(manifestKey(key))
+	if err != nil {
+		return storage.Meta{}, wrap(err, "failed to stat key")
+	}
+
+	m, err := parseManifest(res.Value)
+	if err != nil {
+		return storage.Meta{}, err
+	}
+
+	meta, err := s.client.MetaGet(manifestKey(key))
+	if err != nil {
+		return storage.Meta{}, wrap(err, "failed to stat manifest")
+	}
+
+	return storage.Meta{
+		Size:    m.size,
+		Version: res.CAS,
+		Flags:   res.Flags,
+		TTL:     remainingTTL(meta.TTL),
+	}, nil
+}
+
+// remainingTTL converts ttl reported by memcached, which is negative for items without expiration.
+func remainingTTL(ttl time.Duration) time.Duration {
+	if ttl < 0 {
+		return 0
+	}
+
+	return ttl
+}
+
+func (s *Storage) GetAndTouch(key string, ttl time.Duration) (storage.Item, error) {
+	res, err := s.client.GetAndTouch(key, ttl)
+	if errors.Is(err, memcached.ErrNotFound) {
+		err = s.missed(key, err)
+	}
+	if err != nil {
+		return storage.Item{}, wrap(err, "failed to get and touch key")
+	}
+
+	return storage.Item{
+		Value:   res.Value,
+		Version: res.CAS,
+		Flags:   res.Flags,
+	}, nil
+}
+
+func (s *Storage) Touch(key string, ttl time.Duration) error {
+	err := s.client.Touch(key, ttl)
+	if errors.Is(err, memcached.ErrNotFound) {
+		err = s.missed(key, err)
+	}
+	if err != nil {
+		return wrap(err, "failed to touch key")
+	}
+
+	return nil
+}
+
+func (s *Storage) MultiGet(keys []string) (map[string]storage.Item, error) {
+	res, err := s.client.GetMulti(keys...)
+	if err != nil {
+		return nil, wrap(err, "failed to get keys")
+	}
+
+	items := make(map[string]storage.Item, len(res))
+	for k, v := range res {
+		items[k] = storage.Item{
+			Value:   v.Value,
+			Version: v.CAS,
+			Flags:   v.Flags,
+		}
+	}
+
+	return items, nil
+}
+
+func (s *Storage) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
+	err := s.client.Set(key, value, flags, ttl)
+	if err != nil {
+		return wrap(err, "failed to set key")
+	}
+
+	return s.dropManifests(key)
+}
+
+// Add checks the manifest before adding, so a value committed with PutStream at the same time may still be shadowed.
+func (s *Storage) Add(key string, value []byte, flags uint32, ttl time.Duration) error {
+	chunked, err := s.chunked(key)
+	if err != nil {
+		return err
+	}
+	if chunked {
+		return wrap(storage.ErrNotStored, "failed to add key")
+	}
+
+	err = s.client.Add(key, value, flags, ttl)
+	if err != nil {
+		return wrap(err, "failed to add key")
+	}
+
+	return nil
+}
+
+func (s *Storage) Replace(key string, value []byte, flags uint32, ttl time.Duration) error {
+	err := s.client.Replace(key, value, flags, ttl)
+	if errors.Is(err, memcached.ErrNotStored) {
+		err = s.missed(key, err)
+	}
+	if err != nil {
+		return wrap(err, "failed to replace key")
+	}
+
+	return nil
+}
+
+func (s *Storage) Append(key string, value []byte) error {
+	err := s.client.Append(key, value)
+	if errors.Is(err, memcached.ErrNotStored) {
+		err = s.missed(key, err)
+	}
+	if err != nil {
+		return wrap(err, "failed to append to key")
+	}
+
+	return nil
+}
+
+func (s *Storage) Prepend(key string, value []byte) error {
+	err := s.client.Prepend(key, value)
+	if errors.Is(err, memcached.ErrNotStored) {
+		err = s.missed(key, err)
+	}
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get key")
+		return wrap(err, "failed to prepend to key")
 	}
 
-	return res, nil
+	return nil
 }
 
-func (s *Storage) Set(key string, value []byte) error {
-	err := s.client.Set(key, value)
+func (s *Storage) CompareAndSwap(key string, value []byte, flags uint32, version uint64, ttl time.Duration) error {
+	err := s.client.CompareAndSwap(key, value, flags, ttl, version)
+	if errors.Is(err, memcached.ErrNotFound) {
+		err = s.missed(key, err)
+	}
 	if err != nil {
-		return errors.Wrap(err, "failed to set key")
+		return wrap(err, "failed to compare and swap key")
 	}
 
 	return nil
 }
 
+// Delete removes both plain and chunked values of the key.
 func (s *Storage) Delete(key string) error {
-	err := s.client.Delete(key)
+	errs, err := s.deleteValues(key)
 	if err != nil {
-		return errors.Wrap(err, "failed to delete key")
+		return wrap(err, "failed to delete key")
+	}
+	if errs[0] != nil {
+		return wrap(errs[0], "failed to delete key")
 	}
 
 	return nil
 }
+
+func (s *Storage) MultiSet(entries []storage.Entry) ([]error, error) {
+	batch := make([]memcached.Entry, len(entries))
+	for i, e := range entries {
+		batch[i] = memcached.Entry{
+			Key:   e.Key,
+			Value: e.Value,
+			Flags: e.Flags,
+			TTL:   e.TTL,
+		}
+	}
+
+	errs, err := s.client.SetMulti(batch)
+	if err != nil {
+		return nil, wrap(err, "failed to set keys")
+	}
+
+	written := make([]string, 0, len(entries))
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = wrap(err, "failed to set key")
+		} else {
+			written = append(written, entries[i].Key)
+		}
+	}
+
+	if len(written) > 0 {
+		err = s.dropManifests(written...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return errs, nil
+}
+
+func (s *Storage) MultiDelete(keys []string) ([]error, error) {
+	errs, err := s.deleteValues(keys...)
+	if err != nil {
+		return nil, wrap(err, "failed to delete keys")
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = wrap(err, "failed to delete key")
+		}
+	}
+
+	return errs, nil
+}
+
+func (s *Storage) Increment(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error) {
+	v, err := s.arithmetic(s.client.Increment, key, delta, initial, ttl)
+	if err != nil {
+		return 0, wrap(err, "failed to increment key")
+	}
+
+	return v, nil
+}
+
+func (s *Storage) Decrement(key string, delta uint64, initial *uint64, ttl time.Duration) (uint64, error) {
+	v, err := s.arithmetic(s.client.Decrement, key, delta, initial, ttl)
+	if err != nil {
+		return 0, wrap(err, "failed to decrement key")
+	}
+
+	return v, nil
+}
+
+// arithmetic applies op and creates missing key with initial value. Memcached has no atomic "incr or create" command,
+// so the key is created with add and op is retried if another client created the key first.
+func (s *Storage) arithmetic(
+	op func(key string, delta uint64) (uint64, error),
+	key string, delta uint64, initial *uint64, ttl time.Duration,
+) (uint64, error) {
+	v, err := op(key, delta)
+	if errors.Is(err, memcached.ErrNotFound) {
+		err = s.missed(key, err)
+	}
+	if initial == nil || !errors.Is(err, memcached.ErrNotFound) {
+		return v, err
+	}
+
+	err = s.client.Add(key, []byte(strconv.FormatUint(*initial, 10)), 0, ttl)
+	if err == nil {
+		return *initial, nil
+	}
+	if !errors.Is(err, memcached.ErrNotStored) {
+		return 0, err
+	}
+
+	return op(key, delta)
+}
@@ -0,0 +1,114 @@
+package memcached
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+// fakeClient keeps items in a map and implements only commands used by chunked values, stat, stats and transactions.
+type fakeClient struct {
+	IMemcachedClient
+	items map[string]memcached.Item
+	cas   uint64
+
+	afterGetMulti func() // simulates concurrent clients.
+}
+
+func (c *fakeClient) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
+	c.cas++
+	c.items[key] = memcached.Item{Value: append([]byte(nil), value...), Flags: flags, CAS: c.cas}
+	return nil
+}
+
+func (c *fakeClient) SetMulti(entries []memcached.Entry) ([]error, error) {
+	errs := make([]error, len(entries))
+	for i, e := range entries {
+		errs[i] = c.Set(e.Key, e.Value, e.Flags, e.TTL)
+	}
+	return errs, nil
+}
+
+func (c *fakeClient) Get(key string) ([]byte, error) {
+	item, err := c.Gets(key)
+	return item.Value, err
+}
+
+func (c *fakeClient) Gets(key string) (memcached.Item, error) {
+	item, ok := c.items[key]
+	if !ok {
+		return memcached.Item{}, memcached.ErrNotFound
+	}
+	return item, nil
+}
+
+func (c *fakeClient) MetaGet(key string) (memcached.ItemMeta, error) {
+	item, ok := c.items[key]
+	if !ok {
+		return memcached.ItemMeta{}, memcached.ErrNotFound
+	}
+	return memcached.ItemMeta{Size: len(item.Value), Flags: item.Flags, CAS: item.CAS, TTL: -time.Second}, nil
+}
+
+func (c *fakeClient) Delete(key string) error {
+	if _, ok := c.items[key]; !ok {
+		return memcached.ErrNotFound
+	}
+	delete(c.items, key)
+	return nil
+}
+
+func (c *fakeClient) DeleteMulti(keys ...string) ([]error, error) {
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		errs[i] = c.Delete(key)
+	}
+	return errs, nil
+}
+
+func (c *fakeClient) Add(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if _, ok := c.items[key]; ok {
+		return memcached.ErrNotStored
+	}
+	return c.Set(key, value, flags, ttl)
+}
+
+func (c *fakeClient) CompareAndSwap(key string, value []byte, flags uint32, ttl time.Duration, cas uint64) error {
+	item, ok := c.items[key]
+	if !ok {
+		return memcached.ErrNotFound
+	}
+	if item.CAS != cas {
+		return memcached.ErrCASConflict
+	}
+	return c.Set(key, value, flags, ttl)
+}
+
+func (c *fakeClient) GetMulti(keys ...string) (map[string]memcached.Item, error) {
+	items := make(map[string]memcached.Item)
+	for _, key := range keys {
+		if item, ok := c.items[key]; ok {
+			items[key] = item
+		}
+	}
+	if c.afterGetMulti != nil {
+		c.afterGetMulti()
+	}
+	return items, nil
+}
+
+func (c *fakeClient) Stats() (map[string]string, error) {
+	var size int
+	for _, item := range c.items {
+		size += len(item.Value)
+	}
+
+	return map[string]string{
+		"curr_items": strconv.Itoa(len(c.items)),
+		"bytes":      strconv.Itoa(size),
+		"get_hits":   "3",
+		"get_misses": "1",
+		"version":    "1.6.15",
+	}, nil
+}
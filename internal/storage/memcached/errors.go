@@ -0,0 +1,16 @@
+package memcached
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+)
+
+// wrap adds context to err and translates errors of the client to storage errors.
+func wrap(err error, msg string) error {
+	return errors.Wrap(storage.Translate(err), msg)
+}
+
+func wrapf(err error, format string, args ...any) error {
+	return errors.Wrapf(storage.Translate(err), format, args...)
+}
@@ -0,0 +1,26 @@
+package memcached
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+func TestErrors(t *testing.T) {
+	storage := New(config.MemcachedStorageConfig{})
+	storage.client = &fakeClient{items: make(map[string]memcached.Item)}
+
+	_, err := storage.Get("missing")
+	require.ErrorIs(t, err, storagepkg.ErrNotFound)
+	require.ErrorIs(t, err, memcached.ErrNotFound)
+	require.Equal(t, "failed to get key: not found", err.Error())
+
+	err = storage.Delete("missing")
+	require.ErrorIs(t, err, storagepkg.ErrNotFound)
+
+	require.Nil(t, wrap(nil, "failed to get key"))
+}
@@ -1,8 +1,30 @@
 package memcached
 
+import (
+	"time"
+
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
 type IMemcachedClient interface {
 	Close() error
-	Set(key string, value []byte) error
+	Set(key string, value []byte, flags uint32, ttl time.Duration) error
+	Add(key string, value []byte, flags uint32, ttl time.Duration) error
+	Replace(key string, value []byte, flags uint32, ttl time.Duration) error
+	Append(key string, value []byte) error
+	Prepend(key string, value []byte) error
+	CompareAndSwap(key string, value []byte, flags uint32, ttl time.Duration, cas uint64) error
 	Get(key string) ([]byte, error)
+	Gets(key string) (memcached.Item, error)
+	GetAndTouch(key string, ttl time.Duration) (memcached.Item, error)
+	MetaGet(key string) (memcached.ItemMeta, error)
+	Version() (string, error)
+	Stats() (map[string]string, error)
+	Touch(key string, ttl time.Duration) error
+	GetMulti(keys ...string) (map[string]memcached.Item, error)
 	Delete(key string) error
+	Increment(key string, delta uint64) (uint64, error)
+	Decrement(key string, delta uint64) (uint64, error)
+	SetMulti(entries []memcached.Entry) ([]error, error)
+	DeleteMulti(keys ...string) ([]error, error)
 }
@@ -2,10 +2,10 @@ package memcached
 
 import (
 	"context"
-
-	"github.com/pkg/errors"
+	"strconv"
 
 	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
 	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
 )
 
@@ -35,7 +35,7 @@ func (s *Storage) Run(ctx context.Context) error {
 		s.client, err = memcached.NewConnWithAddress(s.cfg.Address)
 	}
 	if err != nil {
-		return errors.Wrap(err, "failed to create memcached client")
+		return wrap(err, "failed to create memcached client")
 	}
 
 	close(s.readyCh)
@@ -44,6 +44,45 @@ func (s *Storage) Run(ctx context.Context) error {
 	return nil
 }
 
+// Concurrency returns the number of operations that can be served at the same time without waiting for a connection.
+func (s *Storage) Concurrency() int {
+	if s.cfg.UsePool {
+		return s.cfg.PoolSize
+	}
+
+	return 1
+}
+
+// Ping checks that memcached responds to commands.
+func (s *Storage) Ping() error {
+	_, err := s.client.Version()
+	if err != nil {
+		return wrap(err, "failed to get memcached version")
+	}
+
+	return nil
+}
+
+// Stats reports counters of the whole memcached, so keys of other clients and chunks of streamed values are counted.
+func (s *Storage) Stats() (storage.Stats, error) {
+	raw, err := s.client.Stats()
+	if err != nil {
+		return storage.Stats{}, wrap(err, "failed to get memcached stats")
+	}
+
+	return storage.Stats{
+		Keys:  statUint(raw, "curr_items"),
+		Bytes: statUint(raw, "bytes"),
+		Raw:   raw,
+	}, nil
+}
+
+// statUint returns zero for missing and malformed stats as they only inform operators.
+func statUint(stats map[string]string, name string) uint64 {
+	v, _ := strconv.ParseUint(stats[name], 10, 64)
+	return v
+}
+
 func (s *Storage) ReadyCh() <-chan struct{} {
 	return s.readyCh
 }
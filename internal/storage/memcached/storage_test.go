@@ -0,0 +1,33 @@
+package memcached
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+func TestStats(t *testing.T) {
+	storage := New(config.MemcachedStorageConfig{})
+	storage.client = &fakeClient{items: make(map[string]memcached.Item)}
+
+	require.NoError(t, storage.Set("a", []byte("12345"), 0, 0))
+	require.NoError(t, storage.Set("b", []byte("123"), 0, 0))
+
+	stats, err := storage.Stats()
+	require.NoError(t, err)
+	require.Equal(t, storagepkg.Stats{
+		Keys:  2,
+		Bytes: 8,
+		Raw: map[string]string{
+			"curr_items": "2",
+			"bytes":      "8",
+			"get_hits":   "3",
+			"get_misses": "1",
+			"version":    "1.6.15",
+		},
+	}, stats)
+}
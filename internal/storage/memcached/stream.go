@@ -0,0 +1,362 @@
+package memcached
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+// Values written with PutStream are split into chunks stored under separate keys and a manifest that references them.
+// Chunk keys contain a random generation so a new value never overwrites chunks of the previous one, and the manifest
+// is written last, which makes the whole value visible at once. Plain writes and deletes remove the manifest, chunks
+// are left to expire or get evicted as they are unreachable without it. Manifest keys share the namespace with plain
+// keys, so "<key>:manifest" should not be used as a plain key.
+const (
+	chunkSize = 512 << 10 // memcached item size limit (1MB by default) includes key and item header.
+
+	// chunkTTLSlack makes chunks outlive the manifest as they are written earlier than it.
+	chunkTTLSlack = time.Minute
+)
+
+var errChunkMissing = fmt.Errorf("chunk is missing, it was probably evicted: %w", storage.ErrNotFound)
+
+func manifestKey(key string) string {
+	return key + ":manifest"
+}
+
+// chunked reports whether key holds a value written with PutStream.
+func (s *Storage) chunked(key string) (bool, error) {
+	_, err := s.client.Get(manifestKey(key))
+	if errors.Is(err, memcached.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, wrap(err, "failed to get manifest")
+	}
+
+	return true, nil
+}
+
+// missed is called by plain commands that missed the key, it returns storage.ErrChunked instead of err if the key
+// holds a value written with PutStream.
+func (s *Storage) missed(key string, err error) error {
+	chunked, cerr := s.chunked(key)
+	if cerr != nil {
+		return cerr
+	}
+	if chunked {
+		return storage.ErrChunked
+	}
+
+	return err
+}
+
+// dropManifests removes manifests of keys that were just written with plain commands, so the chunked values they
+// replaced do not come back once the plain values expire.
+func (s *Storage) dropManifests(keys ...string) error {
+	manifests := make([]string, len(keys))
+	for i, key := range keys {
+		manifests[i] = manifestKey(key)
+	}
+
+	errs, err := s.client.DeleteMulti(manifests...)
+	if err != nil {
+		return wrap(err, "failed to delete manifests")
+	}
+
+	for _, err := range errs {
+		if err != nil && !manifestMissing(err) {
+			return wrap(err, "failed to delete manifest")
+		}
+	}
+
+	return nil
+}
+
+// deleteValues deletes both plain and chunked values of keys, a key is reported missing only if it had neither.
+func (s *Storage) deleteValues(keys ...string) ([]error, error) {
+	all := make([]string, 0, 2*len(keys))
+	all = append(all, keys...)
+	for _, key := range keys {
+		all = append(all, manifestKey(key))
+	}
+
+	errs, err := s.client.DeleteMulti(all...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]error, len(keys))
+	for i := range keys {
+		plain, manifest := errs[i], errs[len(keys)+i]
+		switch {
+		case plain != nil && !errors.Is(plain, memcached.ErrNotFound):
+			res[i] = plain
+		case manifest != nil && !manifestMissing(manifest):
+			res[i] = manifest
+		case plain != nil && manifest != nil:
+			res[i] = plain
+		}
+	}
+
+	return res, nil
+}
+
+// manifestMissing reports whether err means that there is no manifest, keys close to the size limit can not have one.
+func manifestMissing(err error) bool {
+	return errors.Is(err, memcached.ErrNotFound) || errors.Is(err, memcached.ErrInvalidKey)
+}
+
+// firstChunked returns the first of keys missing in found that holds a value written with PutStream, if there is one.
+func (s *Storage) firstChunked(keys []string, found map[string]memcached.Item) (string, error) {
+	var manifests []string
+	for _, key := range keys {
+		if _, ok := found[key]; !ok {
+			manifests = append(manifests, manifestKey(key))
+		}
+	}
+	if len(manifests) == 0 {
+		return "", nil
+	}
+
+	res, err := s.client.GetMulti(manifests...)
+	if err != nil {
+		return "", wrap(err, "failed to get manifests")
+	}
+
+	for _, key := range keys {
+		if _, ok := res[manifestKey(key)]; ok {
+			return key, nil
+		}
+	}
+
+	return "", nil
+}
+
+func chunkKey(key, generation string, i int) string {
+	return key + ":chunk:" + generation + ":" + strconv.Itoa(i)
+}
+
+type manifest struct {
+	generation string
+	chunks     int
+	size       uint64
+}
+
+func (m manifest) encode() []byte {
+	return []byte(fmt.Sprintf("%s %d %d", m.generation, m.chunks, m.size))
+}
+
+func parseManifest(b []byte) (manifest, error) {
+	var m manifest
+	_, err := fmt.Sscanf(string(b), "%s %d %d", &m.generation, &m.chunks, &m.size)
+	if err != nil {
+		return manifest{}, wrap(err, "failed to parse manifest")
+	}
+
+	return m, nil
+}
+
+func (m manifest) chunkKeys(key string) []string {
+	keys := make([]string, m.chunks)
+	for i := range keys {
+		keys[i] = chunkKey(key, m.generation, i)
+	}
+
+	return keys
+}
+
+func (s *Storage) PutStream(key string, flags uint32, ttl time.Duration) (storage.ChunkWriter, error) {
+	var b [8]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return nil, wrap(err, "failed to generate chunk generation")
+	}
+
+	chunkTTL := ttl
+	if ttl > 0 {
+		chunkTTL += chunkTTLSlack
+	}
+
+	return &chunkWriter{
+		client:   s.client,
+		key:      key,
+		flags:    flags,
+		ttl:      ttl,
+		chunkTTL: chunkTTL,
+		manifest: manifest{generation: hex.EncodeToString(b[:])},
+	}, nil
+}
+
+// GetStream reads plain values too. Set does not remove the manifest, so the plain key is checked first as it is
+// removed when a chunked value is committed and therefore is always newer than the manifest.
+func (s *Storage) GetStream(key string) (storage.ChunkReader, error) {
+	res, err := s.client.Gets(key)
+	if err == nil {
+		return &chunkReader{
+			item: storage.Item{
+				Version: res.CAS,
+				Flags:   res.Flags,
+			},
+			size:   uint64(len(res.Value)),
+			chunks: [][]byte{res.Value},
+		}, nil
+	}
+	if !errors.Is(err, memcached.ErrNotFound) {
+		return nil, wrap(err, "failed to get key")
+	}
+
+	res, err = s.client.Gets(manifestKey(key))
+	if err != nil {
+		return nil, wrap(err, "failed to get manifest")
+	}
+
+	m, err := parseManifest(res.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkReader{
+		client: s.client,
+		item: storage.Item{
+			Version: res.CAS,
+			Flags:   res.Flags,
+		},
+		size: m.size,
+		keys: m.chunkKeys(key),
+	}, nil
+}
+
+type chunkWriter struct {
+	client   IMemcachedClient
+	key      string
+	flags    uint32
+	ttl      time.Duration
+	chunkTTL time.Duration
+	manifest manifest
+	buf      []byte
+}
+
+func (w *chunkWriter) Write(chunk []byte) error {
+	for len(w.buf)+len(chunk) >= chunkSize {
+		n := chunkSize - len(w.buf)
+		w.buf = append(w.buf, chunk[:n]...)
+		chunk = chunk[n:]
+
+		err := w.flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	w.buf = append(w.buf, chunk...)
+
+	return nil
+}
+
+func (w *chunkWriter) flush() error {
+	err := w.client.Set(chunkKey(w.key, w.manifest.generation, w.manifest.chunks), w.buf, 0, w.chunkTTL)
+	if err != nil {
+		return wrap(err, "failed to set chunk")
+	}
+
+	w.manifest.chunks++
+	w.manifest.size += uint64(len(w.buf))
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+func (w *chunkWriter) Commit() error {
+	if len(w.buf) > 0 {
+		err := w.flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	prev, err := w.client.Get(manifestKey(w.key))
+	if err != nil && !errors.Is(err, memcached.ErrNotFound) {
+		return wrap(err, "failed to get previous manifest")
+	}
+
+	err = w.client.Set(manifestKey(w.key), w.manifest.encode(), w.flags, w.ttl)
+	if err != nil {
+		return wrap(err, "failed to set manifest")
+	}
+
+	err = w.client.Delete(w.key)
+	if err != nil && !errors.Is(err, memcached.ErrNotFound) {
+		return wrap(err, "failed to delete plain value")
+	}
+
+	// chunks of the previous value are removed on a best effort basis, leftovers expire or get evicted by memcached.
+	if prev != nil {
+		if m, err := parseManifest(prev); err == nil && m.generation != w.manifest.generation {
+			_, _ = w.client.DeleteMulti(m.chunkKeys(w.key)...)
+		}
+	}
+
+	return nil
+}
+
+func (w *chunkWriter) Abort() error {
+	if w.manifest.chunks == 0 {
+		return nil
+	}
+
+	_, err := w.client.DeleteMulti(w.manifest.chunkKeys(w.key)...)
+	if err != nil {
+		return wrap(err, "failed to delete chunks")
+	}
+
+	return nil
+}
+
+type chunkReader struct {
+	client IMemcachedClient
+	item   storage.Item
+	size   uint64
+	keys   []string // keys of chunks that are not read yet.
+	chunks [][]byte // chunks that are already fetched.
+}
+
+func (r *chunkReader) Item() storage.Item {
+	return r.item
+}
+
+func (r *chunkReader) Size() uint64 {
+	return r.size
+}
+
+func (r *chunkReader) Next() ([]byte, error) {
+	if len(r.chunks) > 0 {
+		chunk := r.chunks[0]
+		r.chunks = r.chunks[1:]
+		return chunk, nil
+	}
+
+	if len(r.keys) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk, err := r.client.Get(r.keys[0])
+	if errors.Is(err, memcached.ErrNotFound) {
+		return nil, errChunkMissing
+	}
+	if err != nil {
+		return nil, wrap(err, "failed to get chunk")
+	}
+
+	r.keys = r.keys[1:]
+
+	return chunk, nil
+}
@@ -0,0 +1,187 @@
+package memcached
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+func readAll(t *testing.T, storage *Storage, key string) ([]byte, uint32) {
+	r, err := storage.GetStream(key)
+	require.NoError(t, err)
+
+	var value []byte
+	for {
+		chunk, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		value = append(value, chunk...)
+	}
+	require.Equal(t, r.Size(), uint64(len(value)))
+
+	return value, r.Item().Flags
+}
+
+func putChunked(t *testing.T, storage *Storage, key string, value []byte) {
+	w, err := storage.PutStream(key, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(value))
+	require.NoError(t, w.Commit())
+}
+
+func TestStream(t *testing.T) {
+	client := &fakeClient{items: make(map[string]memcached.Item)}
+	storage := New(config.MemcachedStorageConfig{})
+	storage.client = client
+
+	value := bytes.Repeat([]byte("0123456789"), chunkSize/4) // 2.5 chunks.
+
+	w, err := storage.PutStream("key", 7, 0)
+	require.NoError(t, err)
+	for i := 0; i < len(value); i += 1000 {
+		end := i + 1000
+		if end > len(value) {
+			end = len(value)
+		}
+		require.NoError(t, w.Write(value[i:end]))
+	}
+
+	_, err = storage.GetStream("key")
+	require.ErrorIs(t, err, memcached.ErrNotFound, "value must not be visible before commit")
+
+	require.NoError(t, w.Commit())
+	require.Len(t, client.items, 4) // manifest and 3 chunks.
+
+	got, flags := readAll(t, storage, "key")
+	require.Equal(t, value, got)
+	require.Equal(t, uint32(7), flags)
+
+	meta, err := storage.Stat("key")
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(value)), meta.Size)
+	require.Equal(t, uint32(7), meta.Flags)
+	require.Zero(t, meta.TTL)
+
+	t.Run("abort keeps previous value", func(t *testing.T) {
+		w, err := storage.PutStream("key", 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, w.Write(bytes.Repeat([]byte("x"), chunkSize+1)))
+		require.NoError(t, w.Abort())
+
+		require.Len(t, client.items, 4)
+		got, _ := readAll(t, storage, "key")
+		require.Equal(t, value, got)
+	})
+
+	t.Run("overwrite removes previous chunks", func(t *testing.T) {
+		w, err := storage.PutStream("key", 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, w.Write([]byte("small")))
+		require.NoError(t, w.Commit())
+
+		require.Len(t, client.items, 2)
+		got, _ := readAll(t, storage, "key")
+		require.Equal(t, []byte("small"), got)
+	})
+
+	t.Run("plain commands refuse chunked value", func(t *testing.T) {
+		_, err := storage.Get("key")
+		require.ErrorIs(t, err, storagepkg.ErrChunked)
+		items, err := storage.MultiGet([]string{"key", "missing"})
+		require.NoError(t, err)
+		require.Empty(t, items)
+
+		err = storage.Add("key", []byte("added"), 0, 0)
+		require.ErrorIs(t, err, storagepkg.ErrNotStored)
+		got, _ := readAll(t, storage, "key")
+		require.Equal(t, []byte("small"), got)
+
+		_, err = storage.Get("missing")
+		require.ErrorIs(t, err, storagepkg.ErrNotFound)
+	})
+
+	t.Run("plain value replaces chunked value", func(t *testing.T) {
+		for name, set := range map[string]func() error{
+			"set": func() error {
+				return storage.Set("key", []byte("plain"), 1, 0)
+			},
+			"multi set": func() error {
+				errs, err := storage.MultiSet([]storagepkg.Entry{{Key: "key", Value: []byte("plain"), Flags: 1}})
+				require.NoError(t, err)
+				return errs[0]
+			},
+			"txn": func() error {
+				_, err := storage.Txn(storagepkg.Txn{Success: []storagepkg.Op{
+					{Type: storagepkg.OpSet, Key: "key", Value: []byte("plain"), Flags: 1},
+				}})
+				return err
+			},
+		} {
+			putChunked(t, storage, "key", []byte("chunked"))
+			require.NoError(t, set(), name)
+
+			got, flags := readAll(t, storage, "key")
+			require.Equal(t, []byte("plain"), got, name)
+			require.Equal(t, uint32(1), flags, name)
+			meta, err := storage.Stat("key")
+			require.NoError(t, err, name)
+			require.Equal(t, uint64(5), meta.Size, name)
+
+			delete(client.items, "key") // plain value expires.
+			_, err = storage.Get("key")
+			require.ErrorIs(t, err, storagepkg.ErrNotFound, name)
+			_, err = storage.GetStream("key")
+			require.ErrorIs(t, err, memcached.ErrNotFound, name)
+		}
+	})
+
+	t.Run("delete removes chunked value", func(t *testing.T) {
+		for name, del := range map[string]func() error{
+			"delete": func() error {
+				return storage.Delete("key")
+			},
+			"multi delete": func() error {
+				errs, err := storage.MultiDelete([]string{"key"})
+				require.NoError(t, err)
+				return errs[0]
+			},
+			"txn": func() error {
+				_, err := storage.Txn(storagepkg.Txn{Success: []storagepkg.Op{{Type: storagepkg.OpDelete, Key: "key"}}})
+				return err
+			},
+		} {
+			putChunked(t, storage, "key", []byte("chunked"))
+			require.NoError(t, del(), name)
+			_, err := storage.GetStream("key")
+			require.ErrorIs(t, err, memcached.ErrNotFound, name)
+		}
+
+		require.ErrorIs(t, storage.Delete("key"), memcached.ErrNotFound)
+		errs, err := storage.MultiDelete([]string{"key"})
+		require.NoError(t, err)
+		require.ErrorIs(t, errs[0], memcached.ErrNotFound)
+	})
+
+	t.Run("evicted chunk", func(t *testing.T) {
+		putChunked(t, storage, "key", value)
+
+		m, err := parseManifest(client.items[manifestKey("key")].Value)
+		require.NoError(t, err)
+		delete(client.items, chunkKey("key", m.generation, 1))
+
+		r, err := storage.GetStream("key")
+		require.NoError(t, err)
+		_, err = r.Next()
+		require.NoError(t, err)
+		_, err = r.Next()
+		require.ErrorIs(t, err, errChunkMissing)
+	})
+}
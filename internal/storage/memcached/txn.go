@@ -0,0 +1,147 @@
+package memcached
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+const guardedDeleteTTL = 10 * time.Second
+
+// Txn is best effort as memcached has no multi-key transactions. Compared keys are read along with their cas values
+// and the first write to each of them is made with cas (or add for a key compared while missing), so a concurrent
+// change of a compared key fails the transaction with a conflict. Ops executed before the conflict are not rolled
+// back. Ops on keys that were not compared and deletes of keys compared while missing are unconditional. Like their
+// plain counterparts, sets and deletes also remove values written with PutStream.
+func (s *Storage) Txn(txn storage.Txn) (storage.TxnResult, error) {
+	keys := make([]string, 0, len(txn.Compares))
+	seen := make(map[string]bool, len(txn.Compares))
+	for _, c := range txn.Compares {
+		if !seen[c.Key] {
+			seen[c.Key] = true
+			keys = append(keys, c.Key)
+		}
+	}
+
+	compared := make(map[string]memcached.Item)
+	if len(keys) > 0 {
+		var err error
+		compared, err = s.client.GetMulti(keys...)
+		if err != nil {
+			return storage.TxnResult{}, wrap(err, "failed to get compared keys")
+		}
+
+		chunked, err := s.firstChunked(keys, compared)
+		if err != nil {
+			return storage.TxnResult{}, err
+		}
+		if chunked != "" {
+			return storage.TxnResult{}, wrapf(storage.ErrChunked, "failed to compare key %s", chunked)
+		}
+	}
+
+	res := storage.TxnResult{Succeeded: true}
+	for _, c := range txn.Compares {
+		var item *storage.Item
+		if v, ok := compared[c.Key]; ok {
+			item = &storage.Item{
+				Value:   v.Value,
+				Version: v.CAS,
+				Flags:   v.Flags,
+			}
+		}
+
+		if !c.Holds(item) {
+			res.Succeeded = false
+			break
+		}
+	}
+
+	ops := txn.Success
+	if !res.Succeeded {
+		ops = txn.Failure
+	}
+
+	// cas values of compared keys that are not written yet, zero means that the key was missing.
+	guarded := make(map[string]uint64, len(keys))
+	for _, key := range keys {
+		guarded[key] = compared[key].CAS
+	}
+
+	res.Results = make([]storage.OpResult, len(ops))
+	for i, op := range ops {
+		var err error
+
+		switch op.Type {
+		case storage.OpGet:
+			var v memcached.Item
+			v, err = s.client.Gets(op.Key)
+			if err == nil {
+				res.Results[i] = storage.OpResult{
+					Item: storage.Item{
+						Value:   v.Value,
+						Version: v.CAS,
+						Flags:   v.Flags,
+					},
+					Found: true,
+				}
+			}
+			if errors.Is(err, memcached.ErrNotFound) {
+				err = nil
+			}
+		case storage.OpSet:
+			cas, ok := guarded[op.Key]
+			delete(guarded, op.Key)
+
+			switch {
+			case !ok:
+				err = s.client.Set(op.Key, op.Value, op.Flags, op.TTL)
+			case cas == 0:
+				err = s.client.Add(op.Key, op.Value, op.Flags, op.TTL)
+				if errors.Is(err, memcached.ErrNotStored) {
+					err = storage.ErrConflict
+				}
+			default:
+				err = s.client.CompareAndSwap(op.Key, op.Value, op.Flags, op.TTL, cas)
+				if errors.Is(err, memcached.ErrCASConflict) || errors.Is(err, memcached.ErrNotFound) {
+					err = storage.ErrConflict
+				}
+			}
+
+			if err == nil {
+				err = s.dropManifests(op.Key)
+			}
+		case storage.OpDelete:
+			cas, ok := guarded[op.Key]
+			delete(guarded, op.Key)
+
+			if ok && cas != 0 {
+				// there is no delete with cas, so the key is rewritten with cas first to detect a concurrent change.
+				// Short ttl makes the key expire soon if the following delete fails.
+				v := compared[op.Key]
+				err = s.client.CompareAndSwap(op.Key, v.Value, v.Flags, guardedDeleteTTL, cas)
+				if errors.Is(err, memcached.ErrCASConflict) || errors.Is(err, memcached.ErrNotFound) {
+					err = storage.ErrConflict
+				}
+			}
+
+			if err == nil {
+				var errs []error
+				errs, err = s.deleteValues(op.Key)
+				if err == nil && !errors.Is(errs[0], memcached.ErrNotFound) {
+					err = errs[0]
+				}
+			}
+		}
+
+		if err != nil {
+			return storage.TxnResult{}, wrapf(err, "failed to execute op on key %s, %d of %d ops were applied",
+				op.Key, i, len(ops))
+		}
+	}
+
+	return res, nil
+}
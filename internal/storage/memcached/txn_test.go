@@ -0,0 +1,105 @@
+package memcached
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	storagepkg "github.com/IlyaFloppy/grpcstore/internal/storage"
+	"github.com/IlyaFloppy/grpcstore/sdk/memcached"
+)
+
+func TestTxn(t *testing.T) {
+	client := &fakeClient{items: make(map[string]memcached.Item)}
+	storage := New(config.MemcachedStorageConfig{})
+	storage.client = client
+
+	require.NoError(t, storage.Set("from", []byte("item"), 0, 0))
+	from, err := storage.Get("from")
+	require.NoError(t, err)
+
+	move := storagepkg.Txn{
+		Compares: []storagepkg.Compare{
+			{Key: "from", Target: storagepkg.CompareVersion, Version: from.Version},
+			{Key: "to", Target: storagepkg.CompareExists, Exists: false},
+		},
+		Success: []storagepkg.Op{
+			{Type: storagepkg.OpSet, Key: "to", Value: []byte("item")},
+			{Type: storagepkg.OpDelete, Key: "from"},
+			{Type: storagepkg.OpGet, Key: "to"},
+		},
+		Failure: []storagepkg.Op{
+			{Type: storagepkg.OpGet, Key: "from"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		res, err := storage.Txn(move)
+		require.NoError(t, err)
+		require.True(t, res.Succeeded)
+		require.False(t, res.Atomic)
+		require.True(t, res.Results[2].Found)
+		require.Equal(t, []byte("item"), res.Results[2].Item.Value)
+		require.NotContains(t, client.items, "from")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		res, err := storage.Txn(move)
+		require.NoError(t, err)
+		require.False(t, res.Succeeded)
+		require.Equal(t, []storagepkg.OpResult{{}}, res.Results)
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		to, err := storage.Get("to")
+		require.NoError(t, err)
+
+		client.afterGetMulti = func() {
+			require.NoError(t, storage.Set("to", []byte("other"), 0, 0))
+		}
+		defer func() { client.afterGetMulti = nil }()
+
+		_, err = storage.Txn(storagepkg.Txn{
+			Compares: []storagepkg.Compare{
+				{Key: "to", Target: storagepkg.CompareVersion, Version: to.Version},
+			},
+			Success: []storagepkg.Op{
+				{Type: storagepkg.OpSet, Key: "from", Value: []byte("item")},
+				{Type: storagepkg.OpSet, Key: "to", Value: []byte("mine")},
+			},
+		})
+		require.ErrorIs(t, err, storagepkg.ErrConflict)
+		require.Equal(t, []byte("other"), client.items["to"].Value)
+		require.Equal(t, []byte("item"), client.items["from"].Value, "preceding ops are not rolled back")
+	})
+	t.Run("guarded delete", func(t *testing.T) {
+		to, err := storage.Get("to")
+		require.NoError(t, err)
+
+		deleteTo := storagepkg.Txn{
+			Compares: []storagepkg.Compare{
+				{Key: "to", Target: storagepkg.CompareVersion, Version: to.Version},
+			},
+			Success: []storagepkg.Op{
+				{Type: storagepkg.OpDelete, Key: "to"},
+			},
+		}
+
+		client.afterGetMulti = func() {
+			require.NoError(t, storage.Set("to", []byte("other"), 0, 0))
+		}
+		_, err = storage.Txn(deleteTo)
+		client.afterGetMulti = nil
+		require.ErrorIs(t, err, storagepkg.ErrConflict)
+		require.Contains(t, client.items, "to")
+
+		to, err = storage.Get("to")
+		require.NoError(t, err)
+		deleteTo.Compares[0].Version = to.Version
+		res, err := storage.Txn(deleteTo)
+		require.NoError(t, err)
+		require.True(t, res.Succeeded)
+		require.NotContains(t, client.items, "to")
+	})
+}
@@ -0,0 +1,16 @@
+package storage
+
+// ChunkWriter stores a value chunk by chunk. The value becomes visible only after Commit, Abort discards written
+// chunks and keeps the previous value.
+type ChunkWriter interface {
+	Write(chunk []byte) error
+	Commit() error
+	Abort() error
+}
+
+// ChunkReader reads a value chunk by chunk.
+type ChunkReader interface {
+	Item() Item // Value is always nil.
+	Size() uint64
+	Next() ([]byte, error) // returns io.EOF after the last chunk.
+}
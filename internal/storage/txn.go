@@ -0,0 +1,66 @@
+package storage
+
+import "time"
+
+type CompareTarget int
+
+const (
+	CompareValue CompareTarget = iota + 1
+	CompareVersion
+	CompareExists
+)
+
+type Compare struct {
+	Key     string
+	Target  CompareTarget
+	Value   []byte
+	Version uint64
+	Exists  bool
+}
+
+// Holds reports whether the compare is true for item, item is nil for a missing key.
+func (c Compare) Holds(item *Item) bool {
+	switch c.Target {
+	case CompareValue:
+		return item != nil && string(item.Value) == string(c.Value)
+	case CompareVersion:
+		return item != nil && item.Version == c.Version
+	case CompareExists:
+		return (item != nil) == c.Exists
+	}
+
+	return false
+}
+
+type OpType int
+
+const (
+	OpGet OpType = iota + 1
+	OpSet
+	OpDelete
+)
+
+type Op struct {
+	Type  OpType
+	Key   string
+	Value []byte
+	Flags uint32
+	TTL   time.Duration
+}
+
+type OpResult struct {
+	Item  Item
+	Found bool // set for OpGet only.
+}
+
+type Txn struct {
+	Compares []Compare
+	Success  []Op
+	Failure  []Op
+}
+
+type TxnResult struct {
+	Succeeded bool
+	Results   []OpResult
+	Atomic    bool // false if a concurrent client could observe or change keys in the middle of the transaction.
+}
@@ -18,8 +18,31 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GRPCStoreServiceClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResult, error)
+	Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResult, error)
 	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResult, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResult, error)
+	CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...grpc.CallOption) (*CompareAndSwapResult, error)
+	MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResult, error)
+	MultiSet(ctx context.Context, in *MultiSetRequest, opts ...grpc.CallOption) (*MultiSetResult, error)
+	MultiDelete(ctx context.Context, in *MultiDeleteRequest, opts ...grpc.CallOption) (*MultiDeleteResult, error)
+	Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*IncrementResult, error)
+	Decrement(ctx context.Context, in *DecrementRequest, opts ...grpc.CallOption) (*DecrementResult, error)
+	Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResult, error)
+	Prepend(ctx context.Context, in *PrependRequest, opts ...grpc.CallOption) (*PrependResult, error)
+	Touch(ctx context.Context, in *TouchRequest, opts ...grpc.CallOption) (*TouchResult, error)
+	GetAndTouch(ctx context.Context, in *GetAndTouchRequest, opts ...grpc.CallOption) (*GetAndTouchResult, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (GRPCStoreService_WatchClient, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResult, error)
+	Pipeline(ctx context.Context, opts ...grpc.CallOption) (GRPCStoreService_PipelineClient, error)
+	PutStream(ctx context.Context, opts ...grpc.CallOption) (GRPCStoreService_PutStreamClient, error)
+	GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (GRPCStoreService_GetStreamClient, error)
+	Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResult, error)
+	LeaseGrant(ctx context.Context, in *LeaseGrantRequest, opts ...grpc.CallOption) (*LeaseGrantResult, error)
+	LeaseRevoke(ctx context.Context, in *LeaseRevokeRequest, opts ...grpc.CallOption) (*LeaseRevokeResult, error)
+	LeaseKeepAlive(ctx context.Context, opts ...grpc.CallOption) (GRPCStoreService_LeaseKeepAliveClient, error)
+	Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResult, error)
+	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResult, error)
+	RefreshLock(ctx context.Context, in *RefreshLockRequest, opts ...grpc.CallOption) (*RefreshLockResult, error)
 }
 
 type gRPCStoreServiceClient struct {
@@ -39,6 +62,15 @@ func (c *gRPCStoreServiceClient) Get(ctx context.Context, in *GetRequest, opts .
 	return out, nil
 }
 
+func (c *gRPCStoreServiceClient) Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResult, error) {
+	out := new(StatResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Stat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gRPCStoreServiceClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResult, error) {
 	out := new(SetResult)
 	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Set", in, out, opts...)
@@ -57,13 +89,349 @@ func (c *gRPCStoreServiceClient) Delete(ctx context.Context, in *DeleteRequest,
 	return out, nil
 }
 
+func (c *gRPCStoreServiceClient) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...grpc.CallOption) (*CompareAndSwapResult, error) {
+	out := new(CompareAndSwapResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/CompareAndSwap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) MultiGet(ctx context.Context, in *MultiGetRequest, opts ...grpc.CallOption) (*MultiGetResult, error) {
+	out := new(MultiGetResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/MultiGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) MultiSet(ctx context.Context, in *MultiSetRequest, opts ...grpc.CallOption) (*MultiSetResult, error) {
+	out := new(MultiSetResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/MultiSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) MultiDelete(ctx context.Context, in *MultiDeleteRequest, opts ...grpc.CallOption) (*MultiDeleteResult, error) {
+	out := new(MultiDeleteResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/MultiDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*IncrementResult, error) {
+	out := new(IncrementResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Increment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Decrement(ctx context.Context, in *DecrementRequest, opts ...grpc.CallOption) (*DecrementResult, error) {
+	out := new(DecrementResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Decrement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResult, error) {
+	out := new(AppendResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Append", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Prepend(ctx context.Context, in *PrependRequest, opts ...grpc.CallOption) (*PrependResult, error) {
+	out := new(PrependResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Prepend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Touch(ctx context.Context, in *TouchRequest, opts ...grpc.CallOption) (*TouchResult, error) {
+	out := new(TouchResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Touch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) GetAndTouch(ctx context.Context, in *GetAndTouchRequest, opts ...grpc.CallOption) (*GetAndTouchResult, error) {
+	out := new(GetAndTouchResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/GetAndTouch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (GRPCStoreService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GRPCStoreService_serviceDesc.Streams[0], "/pb.GRPCStoreService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gRPCStoreServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GRPCStoreService_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type gRPCStoreServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *gRPCStoreServiceWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gRPCStoreServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResult, error) {
+	out := new(ListResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Pipeline(ctx context.Context, opts ...grpc.CallOption) (GRPCStoreService_PipelineClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GRPCStoreService_serviceDesc.Streams[1], "/pb.GRPCStoreService/Pipeline", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gRPCStoreServicePipelineClient{stream}
+	return x, nil
+}
+
+type GRPCStoreService_PipelineClient interface {
+	Send(*PipelineRequest) error
+	Recv() (*PipelineResult, error)
+	grpc.ClientStream
+}
+
+type gRPCStoreServicePipelineClient struct {
+	grpc.ClientStream
+}
+
+func (x *gRPCStoreServicePipelineClient) Send(m *PipelineRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gRPCStoreServicePipelineClient) Recv() (*PipelineResult, error) {
+	m := new(PipelineResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gRPCStoreServiceClient) PutStream(ctx context.Context, opts ...grpc.CallOption) (GRPCStoreService_PutStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GRPCStoreService_serviceDesc.Streams[2], "/pb.GRPCStoreService/PutStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gRPCStoreServicePutStreamClient{stream}
+	return x, nil
+}
+
+type GRPCStoreService_PutStreamClient interface {
+	Send(*PutStreamRequest) error
+	CloseAndRecv() (*PutStreamResult, error)
+	grpc.ClientStream
+}
+
+type gRPCStoreServicePutStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *gRPCStoreServicePutStreamClient) Send(m *PutStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gRPCStoreServicePutStreamClient) CloseAndRecv() (*PutStreamResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PutStreamResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gRPCStoreServiceClient) GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (GRPCStoreService_GetStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GRPCStoreService_serviceDesc.Streams[3], "/pb.GRPCStoreService/GetStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gRPCStoreServiceGetStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GRPCStoreService_GetStreamClient interface {
+	Recv() (*GetStreamResult, error)
+	grpc.ClientStream
+}
+
+type gRPCStoreServiceGetStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *gRPCStoreServiceGetStreamClient) Recv() (*GetStreamResult, error) {
+	m := new(GetStreamResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gRPCStoreServiceClient) Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResult, error) {
+	out := new(TxnResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Txn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) LeaseGrant(ctx context.Context, in *LeaseGrantRequest, opts ...grpc.CallOption) (*LeaseGrantResult, error) {
+	out := new(LeaseGrantResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/LeaseGrant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) LeaseRevoke(ctx context.Context, in *LeaseRevokeRequest, opts ...grpc.CallOption) (*LeaseRevokeResult, error) {
+	out := new(LeaseRevokeResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/LeaseRevoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) LeaseKeepAlive(ctx context.Context, opts ...grpc.CallOption) (GRPCStoreService_LeaseKeepAliveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GRPCStoreService_serviceDesc.Streams[4], "/pb.GRPCStoreService/LeaseKeepAlive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gRPCStoreServiceLeaseKeepAliveClient{stream}
+	return x, nil
+}
+
+type GRPCStoreService_LeaseKeepAliveClient interface {
+	Send(*LeaseKeepAliveRequest) error
+	Recv() (*LeaseKeepAliveResult, error)
+	grpc.ClientStream
+}
+
+type gRPCStoreServiceLeaseKeepAliveClient struct {
+	grpc.ClientStream
+}
+
+func (x *gRPCStoreServiceLeaseKeepAliveClient) Send(m *LeaseKeepAliveRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gRPCStoreServiceLeaseKeepAliveClient) Recv() (*LeaseKeepAliveResult, error) {
+	m := new(LeaseKeepAliveResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gRPCStoreServiceClient) Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResult, error) {
+	out := new(LockResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Lock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResult, error) {
+	out := new(UnlockResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/Unlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gRPCStoreServiceClient) RefreshLock(ctx context.Context, in *RefreshLockRequest, opts ...grpc.CallOption) (*RefreshLockResult, error) {
+	out := new(RefreshLockResult)
+	err := c.cc.Invoke(ctx, "/pb.GRPCStoreService/RefreshLock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GRPCStoreServiceServer is the server API for GRPCStoreService service.
 // All implementations must embed UnimplementedGRPCStoreServiceServer
 // for forward compatibility
 type GRPCStoreServiceServer interface {
 	Get(context.Context, *GetRequest) (*GetResult, error)
+	Stat(context.Context, *StatRequest) (*StatResult, error)
 	Set(context.Context, *SetRequest) (*SetResult, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResult, error)
+	CompareAndSwap(context.Context, *CompareAndSwapRequest) (*CompareAndSwapResult, error)
+	MultiGet(context.Context, *MultiGetRequest) (*MultiGetResult, error)
+	MultiSet(context.Context, *MultiSetRequest) (*MultiSetResult, error)
+	MultiDelete(context.Context, *MultiDeleteRequest) (*MultiDeleteResult, error)
+	Increment(context.Context, *IncrementRequest) (*IncrementResult, error)
+	Decrement(context.Context, *DecrementRequest) (*DecrementResult, error)
+	Append(context.Context, *AppendRequest) (*AppendResult, error)
+	Prepend(context.Context, *PrependRequest) (*PrependResult, error)
+	Touch(context.Context, *TouchRequest) (*TouchResult, error)
+	GetAndTouch(context.Context, *GetAndTouchRequest) (*GetAndTouchResult, error)
+	Watch(*WatchRequest, GRPCStoreService_WatchServer) error
+	List(context.Context, *ListRequest) (*ListResult, error)
+	Pipeline(GRPCStoreService_PipelineServer) error
+	PutStream(GRPCStoreService_PutStreamServer) error
+	GetStream(*GetStreamRequest, GRPCStoreService_GetStreamServer) error
+	Txn(context.Context, *TxnRequest) (*TxnResult, error)
+	LeaseGrant(context.Context, *LeaseGrantRequest) (*LeaseGrantResult, error)
+	LeaseRevoke(context.Context, *LeaseRevokeRequest) (*LeaseRevokeResult, error)
+	LeaseKeepAlive(GRPCStoreService_LeaseKeepAliveServer) error
+	Lock(context.Context, *LockRequest) (*LockResult, error)
+	Unlock(context.Context, *UnlockRequest) (*UnlockResult, error)
+	RefreshLock(context.Context, *RefreshLockRequest) (*RefreshLockResult, error)
 	mustEmbedUnimplementedGRPCStoreServiceServer()
 }
 
@@ -74,12 +442,81 @@ type UnimplementedGRPCStoreServiceServer struct {
 func (UnimplementedGRPCStoreServiceServer) Get(context.Context, *GetRequest) (*GetResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
+func (UnimplementedGRPCStoreServiceServer) Stat(context.Context, *StatRequest) (*StatResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stat not implemented")
+}
 func (UnimplementedGRPCStoreServiceServer) Set(context.Context, *SetRequest) (*SetResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
 }
 func (UnimplementedGRPCStoreServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedGRPCStoreServiceServer) CompareAndSwap(context.Context, *CompareAndSwapRequest) (*CompareAndSwapResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndSwap not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) MultiGet(context.Context, *MultiGetRequest) (*MultiGetResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiGet not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) MultiSet(context.Context, *MultiSetRequest) (*MultiSetResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiSet not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) MultiDelete(context.Context, *MultiDeleteRequest) (*MultiDeleteResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiDelete not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Increment(context.Context, *IncrementRequest) (*IncrementResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Increment not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Decrement(context.Context, *DecrementRequest) (*DecrementResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decrement not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Append(context.Context, *AppendRequest) (*AppendResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Prepend(context.Context, *PrependRequest) (*PrependResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prepend not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Touch(context.Context, *TouchRequest) (*TouchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Touch not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) GetAndTouch(context.Context, *GetAndTouchRequest) (*GetAndTouchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAndTouch not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Watch(*WatchRequest, GRPCStoreService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) List(context.Context, *ListRequest) (*ListResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Pipeline(GRPCStoreService_PipelineServer) error {
+	return status.Errorf(codes.Unimplemented, "method Pipeline not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) PutStream(GRPCStoreService_PutStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PutStream not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) GetStream(*GetStreamRequest, GRPCStoreService_GetStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetStream not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Txn(context.Context, *TxnRequest) (*TxnResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Txn not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) LeaseGrant(context.Context, *LeaseGrantRequest) (*LeaseGrantResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseGrant not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) LeaseRevoke(context.Context, *LeaseRevokeRequest) (*LeaseRevokeResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseRevoke not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) LeaseKeepAlive(GRPCStoreService_LeaseKeepAliveServer) error {
+	return status.Errorf(codes.Unimplemented, "method LeaseKeepAlive not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Lock(context.Context, *LockRequest) (*LockResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lock not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) Unlock(context.Context, *UnlockRequest) (*UnlockResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
+func (UnimplementedGRPCStoreServiceServer) RefreshLock(context.Context, *RefreshLockRequest) (*RefreshLockResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshLock not implemented")
+}
 func (UnimplementedGRPCStoreServiceServer) mustEmbedUnimplementedGRPCStoreServiceServer() {}
 
 // UnsafeGRPCStoreServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -111,6 +548,24 @@ func _GRPCStoreService_Get_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GRPCStoreService_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Stat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Stat(ctx, req.(*StatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GRPCStoreService_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetRequest)
 	if err := dec(in); err != nil {
@@ -147,21 +602,672 @@ func _GRPCStoreService_Delete_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-var _GRPCStoreService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "pb.GRPCStoreService",
-	HandlerType: (*GRPCStoreServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _GRPCStoreService_Get_Handler,
-		},
-		{
-			MethodName: "Set",
-			Handler:    _GRPCStoreService_Set_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _GRPCStoreService_Delete_Handler,
+func _GRPCStoreService_CompareAndSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndSwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).CompareAndSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/CompareAndSwap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).CompareAndSwap(ctx, req.(*CompareAndSwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_MultiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).MultiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/MultiGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).MultiGet(ctx, req.(*MultiGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_MultiSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).MultiSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/MultiSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).MultiSet(ctx, req.(*MultiSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_MultiDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).MultiDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/MultiDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).MultiDelete(ctx, req.(*MultiDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Increment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Increment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Increment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Increment(ctx, req.(*IncrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Decrement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Decrement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Decrement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Decrement(ctx, req.(*DecrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Append(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Append",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Append(ctx, req.(*AppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Prepend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrependRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Prepend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Prepend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Prepend(ctx, req.(*PrependRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Touch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Touch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Touch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Touch(ctx, req.(*TouchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_GetAndTouch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAndTouchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).GetAndTouch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/GetAndTouch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).GetAndTouch(ctx, req.(*GetAndTouchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GRPCStoreServiceServer).Watch(m, &gRPCStoreServiceWatchServer{stream})
+}
+
+type GRPCStoreService_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type gRPCStoreServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *gRPCStoreServiceWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GRPCStoreService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Pipeline_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GRPCStoreServiceServer).Pipeline(&gRPCStoreServicePipelineServer{stream})
+}
+
+type GRPCStoreService_PipelineServer interface {
+	Send(*PipelineResult) error
+	Recv() (*PipelineRequest, error)
+	grpc.ServerStream
+}
+
+type gRPCStoreServicePipelineServer struct {
+	grpc.ServerStream
+}
+
+func (x *gRPCStoreServicePipelineServer) Send(m *PipelineResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gRPCStoreServicePipelineServer) Recv() (*PipelineRequest, error) {
+	m := new(PipelineRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GRPCStoreService_PutStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GRPCStoreServiceServer).PutStream(&gRPCStoreServicePutStreamServer{stream})
+}
+
+type GRPCStoreService_PutStreamServer interface {
+	SendAndClose(*PutStreamResult) error
+	Recv() (*PutStreamRequest, error)
+	grpc.ServerStream
+}
+
+type gRPCStoreServicePutStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *gRPCStoreServicePutStreamServer) SendAndClose(m *PutStreamResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gRPCStoreServicePutStreamServer) Recv() (*PutStreamRequest, error) {
+	m := new(PutStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GRPCStoreService_GetStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GRPCStoreServiceServer).GetStream(m, &gRPCStoreServiceGetStreamServer{stream})
+}
+
+type GRPCStoreService_GetStreamServer interface {
+	Send(*GetStreamResult) error
+	grpc.ServerStream
+}
+
+type gRPCStoreServiceGetStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *gRPCStoreServiceGetStreamServer) Send(m *GetStreamResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GRPCStoreService_Txn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Txn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Txn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Txn(ctx, req.(*TxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_LeaseGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseGrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).LeaseGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/LeaseGrant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).LeaseGrant(ctx, req.(*LeaseGrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_LeaseRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).LeaseRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/LeaseRevoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).LeaseRevoke(ctx, req.(*LeaseRevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_LeaseKeepAlive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GRPCStoreServiceServer).LeaseKeepAlive(&gRPCStoreServiceLeaseKeepAliveServer{stream})
+}
+
+type GRPCStoreService_LeaseKeepAliveServer interface {
+	Send(*LeaseKeepAliveResult) error
+	Recv() (*LeaseKeepAliveRequest, error)
+	grpc.ServerStream
+}
+
+type gRPCStoreServiceLeaseKeepAliveServer struct {
+	grpc.ServerStream
+}
+
+func (x *gRPCStoreServiceLeaseKeepAliveServer) Send(m *LeaseKeepAliveResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gRPCStoreServiceLeaseKeepAliveServer) Recv() (*LeaseKeepAliveRequest, error) {
+	m := new(LeaseKeepAliveRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GRPCStoreService_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Lock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Lock(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).Unlock(ctx, req.(*UnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GRPCStoreService_RefreshLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GRPCStoreServiceServer).RefreshLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.GRPCStoreService/RefreshLock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GRPCStoreServiceServer).RefreshLock(ctx, req.(*RefreshLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GRPCStoreService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.GRPCStoreService",
+	HandlerType: (*GRPCStoreServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _GRPCStoreService_Get_Handler,
+		},
+		{
+			MethodName: "Stat",
+			Handler:    _GRPCStoreService_Stat_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _GRPCStoreService_Set_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _GRPCStoreService_Delete_Handler,
+		},
+		{
+			MethodName: "CompareAndSwap",
+			Handler:    _GRPCStoreService_CompareAndSwap_Handler,
+		},
+		{
+			MethodName: "MultiGet",
+			Handler:    _GRPCStoreService_MultiGet_Handler,
+		},
+		{
+			MethodName: "MultiSet",
+			Handler:    _GRPCStoreService_MultiSet_Handler,
+		},
+		{
+			MethodName: "MultiDelete",
+			Handler:    _GRPCStoreService_MultiDelete_Handler,
+		},
+		{
+			MethodName: "Increment",
+			Handler:    _GRPCStoreService_Increment_Handler,
+		},
+		{
+			MethodName: "Decrement",
+			Handler:    _GRPCStoreService_Decrement_Handler,
+		},
+		{
+			MethodName: "Append",
+			Handler:    _GRPCStoreService_Append_Handler,
+		},
+		{
+			MethodName: "Prepend",
+			Handler:    _GRPCStoreService_Prepend_Handler,
+		},
+		{
+			MethodName: "Touch",
+			Handler:    _GRPCStoreService_Touch_Handler,
+		},
+		{
+			MethodName: "GetAndTouch",
+			Handler:    _GRPCStoreService_GetAndTouch_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _GRPCStoreService_List_Handler,
+		},
+		{
+			MethodName: "Txn",
+			Handler:    _GRPCStoreService_Txn_Handler,
+		},
+		{
+			MethodName: "LeaseGrant",
+			Handler:    _GRPCStoreService_LeaseGrant_Handler,
+		},
+		{
+			MethodName: "LeaseRevoke",
+			Handler:    _GRPCStoreService_LeaseRevoke_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _GRPCStoreService_Lock_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _GRPCStoreService_Unlock_Handler,
+		},
+		{
+			MethodName: "RefreshLock",
+			Handler:    _GRPCStoreService_RefreshLock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _GRPCStoreService_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Pipeline",
+			Handler:       _GRPCStoreService_Pipeline_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PutStream",
+			Handler:       _GRPCStoreService_PutStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetStream",
+			Handler:       _GRPCStoreService_GetStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "LeaseKeepAlive",
+			Handler:       _GRPCStoreService_LeaseKeepAlive_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "grpcstore.proto",
+}
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// GetDescriptor returns descriptors of this file and all files it imports, so that requests can be built
+	// dynamically without .proto files.
+	GetDescriptor(ctx context.Context, in *GetDescriptorRequest, opts ...grpc.CallOption) (*GetDescriptorResult, error)
+	// GetStats reports usage of the server and of the storage behind it.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResult, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetDescriptor(ctx context.Context, in *GetDescriptorRequest, opts ...grpc.CallOption) (*GetDescriptorResult, error) {
+	out := new(GetDescriptorResult)
+	err := c.cc.Invoke(ctx, "/pb.AdminService/GetDescriptor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResult, error) {
+	out := new(GetStatsResult)
+	err := c.cc.Invoke(ctx, "/pb.AdminService/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	// GetDescriptor returns descriptors of this file and all files it imports, so that requests can be built
+	// dynamically without .proto files.
+	GetDescriptor(context.Context, *GetDescriptorRequest) (*GetDescriptorResult, error)
+	// GetStats reports usage of the server and of the storage behind it.
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResult, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) GetDescriptor(context.Context, *GetDescriptorRequest) (*GetDescriptorResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDescriptor not implemented")
+}
+func (UnimplementedAdminServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&_AdminService_serviceDesc, srv)
+}
+
+func _AdminService_GetDescriptor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDescriptorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetDescriptor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.AdminService/GetDescriptor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetDescriptor(ctx, req.(*GetDescriptorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.AdminService/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AdminService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDescriptor",
+			Handler:    _AdminService_GetDescriptor_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _AdminService_GetStats_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+type Item struct {
+	Value   []byte
+	Version uint64
+	Flags   uint32
+}
+
+// Client is safe for concurrent use.
+type Client struct {
+	opts    options
+	conns   []*grpc.ClientConn
+	clients []pb.GRPCStoreServiceClient
+	next    uint32
+}
+
+// New connects to grpcstore at addr. Connections are established in background, so New does not fail when the
+// server is down.
+func New(addr string, opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.poolSize <= 0 {
+		o.poolSize = defaultPoolSize
+	}
+	if o.maxRetries < 0 || o.minBackoff < 0 || o.maxBackoff < 0 {
+		return nil, errors.New("retries and backoff must not be negative")
+	}
+
+	creds := insecure.NewCredentials()
+	if o.tls != nil {
+		creds = credentials.NewTLS(o.tls)
+	}
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, o.dialOptions...)
+
+	c := &Client{opts: o}
+	for i := 0; i < o.poolSize; i++ {
+		conn, err := grpc.Dial(addr, dialOptions...)
+		if err != nil {
+			_ = c.Close()
+			return nil, errors.Wrap(err, "failed to dial")
+		}
+
+		c.conns = append(c.conns, conn)
+		c.clients = append(c.clients, pb.NewGRPCStoreServiceClient(conn))
+	}
+
+	return c, nil
+}
+
+func (c *Client) Close() error {
+	var res error
+	for _, conn := range c.conns {
+		err := conn.Close()
+		if err != nil && res == nil {
+			res = err
+		}
+	}
+
+	return res
+}
+
+// GRPC returns a generated client for calls that Client does not wrap. It shares connections with Client, but calls
+// are not retried and errors are not converted.
+func (c *Client) GRPC() pb.GRPCStoreServiceClient {
+	return c.pick()
+}
+
+func (c *Client) Get(ctx context.Context, key string) (Item, error) {
+	var res *pb.GetResult
+	err := c.call(ctx, func(ctx context.Context, client pb.GRPCStoreServiceClient) error {
+		var err error
+		res, err = client.Get(ctx, &pb.GetRequest{Key: key})
+		return err
+	})
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{
+		Value:   res.GetValue(),
+		Version: res.GetVersion(),
+		Flags:   res.GetFlags(),
+	}, nil
+}
+
+func (c *Client) Set(ctx context.Context, key string, value []byte, opts ...SetOption) error {
+	var o setOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	req := &pb.SetRequest{
+		Key:   key,
+		Value: value,
+		Flags: o.flags,
+	}
+	if o.ttl > 0 {
+		req.Ttl = durationpb.New(o.ttl)
+	}
+	switch o.mode {
+	case modeSet:
+		req.Mode = pb.SetMode_SET_MODE_SET
+	case modeAdd:
+		req.Mode = pb.SetMode_SET_MODE_ADD
+	case modeReplace:
+		req.Mode = pb.SetMode_SET_MODE_REPLACE
+	}
+
+	return c.call(ctx, func(ctx context.Context, client pb.GRPCStoreServiceClient) error {
+		_, err := client.Set(ctx, req)
+		return err
+	})
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	return c.call(ctx, func(ctx context.Context, client pb.GRPCStoreServiceClient) error {
+		_, err := client.Delete(ctx, &pb.DeleteRequest{Key: key})
+		return err
+	})
+}
+
+// call makes attempts until one succeeds, fails with a code other than Unavailable or retries are exhausted.
+func (c *Client) call(ctx context.Context, fn func(context.Context, pb.GRPCStoreServiceClient) error) error {
+	if len(c.opts.metadata) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, c.opts.metadata...)
+	}
+
+	backoff := c.opts.minBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, fn)
+		if err == nil {
+			return nil
+		}
+		if status.Code(err) != codes.Unavailable || attempt >= c.opts.maxRetries {
+			return convertError(err)
+		}
+
+		timer := time.NewTimer(backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))) //nolint:gosec
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > c.opts.maxBackoff {
+			backoff = c.opts.maxBackoff
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, fn func(context.Context, pb.GRPCStoreServiceClient) error) error {
+	if c.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.timeout)
+		defer cancel()
+	}
+
+	return fn(ctx, c.pick())
+}
+
+func (c *Client) pick() pb.GRPCStoreServiceClient {
+	i := atomic.AddUint32(&c.next, 1)
+	return c.clients[int(i)%len(c.clients)]
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/IlyaFloppy/grpcstore/internal/config"
+	"github.com/IlyaFloppy/grpcstore/internal/server"
+	"github.com/IlyaFloppy/grpcstore/internal/storage/inmemory"
+	"github.com/IlyaFloppy/grpcstore/public-api/pb"
+)
+
+// testServer serves the real API over inmemory storage and can fail or block Get calls.
+type testServer struct {
+	*server.Server
+
+	mu          sync.Mutex
+	unavailable int // number of Get calls to fail with Unavailable.
+	block       bool
+	calls       int
+	md          metadata.MD
+}
+
+func (s *testServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResult, error) {
+	s.mu.Lock()
+	s.calls++
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	fail, block := s.unavailable > 0, s.block
+	if fail {
+		s.unavailable--
+	}
+	s.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if fail {
+		return nil, status.Error(codes.Unavailable, "try later")
+	}
+
+	return s.Server.Get(ctx, req)
+}
+
+func newTestClient(t *testing.T, opts ...Option) (*Client, *testServer) {
+	t.Helper()
+
+	srv := &testServer{
+		Server: server.New(zerolog.Nop(), config.ServerConfig{}, inmemory.New(config.InMemoryStorageConfig{}), nil),
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGRPCStoreServiceServer(grpcServer, srv)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	opts = append(opts, WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})))
+	c, err := New("bufnet", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, c.Close())
+	})
+
+	return c, srv
+}
+
+func TestClient(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("get set delete", func(t *testing.T) {
+		c, _ := newTestClient(t, WithPoolSize(2))
+		ctx := context.Background()
+
+		_, err := c.Get(ctx, "key")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		require.NoError(t, c.Set(ctx, "key", []byte("12345"), WithFlags(7), WithTTL(time.Minute)))
+		item, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []byte("12345"), item.Value)
+		require.Equal(t, uint32(7), item.Flags)
+		require.NotZero(t, item.Version)
+
+		err = c.Set(ctx, "key", []byte("1"), IfNotExists())
+		require.ErrorIs(t, err, ErrExists)
+		err = c.Set(ctx, "other", []byte("1"), IfExists())
+		require.ErrorIs(t, err, ErrNotFound)
+		err = c.Set(ctx, "key", []byte("1"), WithTTL(-time.Second))
+		require.NoError(t, err) // negative ttl is not sent.
+
+		require.NoError(t, c.Delete(ctx, "key"))
+		_, err = c.Get(ctx, "key")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		c, srv := newTestClient(t, WithRetries(2, time.Millisecond, 2*time.Millisecond))
+		ctx := context.Background()
+		require.NoError(t, c.Set(ctx, "key", []byte("1")))
+
+		srv.unavailable = 2
+		_, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, 3, srv.calls)
+
+		srv.unavailable, srv.calls = 3, 0
+		_, err = c.Get(ctx, "key")
+		require.ErrorIs(t, err, ErrUnavailable)
+		require.Equal(t, 3, srv.calls)
+	})
+
+	t.Run("negative retries", func(t *testing.T) {
+		for _, opt := range []Option{
+			WithRetries(-1, time.Millisecond, time.Second),
+			WithRetries(1, -time.Millisecond, time.Second),
+			WithRetries(1, time.Millisecond, -time.Second),
+		} {
+			c, err := New("bufnet", opt)
+			require.Error(t, err)
+			require.Nil(t, c)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c, srv := newTestClient(t, WithTimeout(10*time.Millisecond))
+		srv.block = true
+
+		_, err := c.Get(context.Background(), "key")
+		require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		c, srv := newTestClient(t, WithBearerToken("secret"), WithMetadata("x-team", "storage"))
+
+		_, err := c.Get(context.Background(), "key")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Equal(t, []string{"Bearer secret"}, srv.md.Get("authorization"))
+		require.Equal(t, []string{"storage"}, srv.md.Get("x-team"))
+	})
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound        = errors.New("key not found")
+	ErrExists          = errors.New("key already exists")
+	ErrConflict        = errors.New("version mismatch")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrUnavailable     = errors.New("server is unavailable")
+	ErrUnimplemented   = errors.New("not supported by server")
+)
+
+// statusError keeps the grpc status, so that status.Convert and status details still work with errors of the client.
+type statusError struct {
+	sentinel error
+	st       *status.Status
+}
+
+func (e *statusError) Error() string {
+	return e.sentinel.Error() + ": " + e.st.Message()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.sentinel
+}
+
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.st
+}
+
+// convertError maps grpc errors to sentinel errors, errors with other codes are returned as is.
+func convertError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var sentinel error
+	switch st.Code() { //nolint:exhaustive
+	case codes.NotFound:
+		sentinel = ErrNotFound
+	case codes.AlreadyExists:
+		sentinel = ErrExists
+	case codes.Aborted:
+		sentinel = ErrConflict
+	case codes.InvalidArgument:
+		sentinel = ErrInvalidArgument
+	case codes.Unavailable:
+		sentinel = ErrUnavailable
+	case codes.Unimplemented:
+		sentinel = ErrUnimplemented
+	default:
+		return err
+	}
+
+	return &statusError{
+		sentinel: sentinel,
+		st:       st,
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"crypto/tls"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultPoolSize   = 1
+	defaultTimeout    = 5 * time.Second
+	defaultMaxRetries = 3
+	defaultMinBackoff = 50 * time.Millisecond
+	defaultMaxBackoff = time.Second
+)
+
+type options struct {
+	poolSize    int
+	timeout     time.Duration
+	maxRetries  int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	tls         *tls.Config
+	metadata    []string // key value pairs.
+	dialOptions []grpc.DialOption
+}
+
+func defaultOptions() options {
+	return options{
+		poolSize:   defaultPoolSize,
+		timeout:    defaultTimeout,
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+}
+
+type Option func(*options)
+
+// WithPoolSize sets the number of connections, calls are spread over them in turn.
+func WithPoolSize(size int) Option {
+	return func(o *options) {
+		o.poolSize = size
+	}
+}
+
+// WithTimeout limits every attempt of a call, zero disables the limit and leaves it to the caller context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetries sets how many times a call is retried when the server is unavailable. Backoff doubles after every
+// attempt from min up to max, a random jitter of up to half of it is added. New fails if any of them is negative.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.maxRetries = maxRetries
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithTLS enables TLS, connections are not encrypted by default.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tls = cfg
+	}
+}
+
+// WithMetadata adds a header to every call.
+func WithMetadata(key, value string) Option {
+	return func(o *options) {
+		o.metadata = append(o.metadata, key, value)
+	}
+}
+
+// WithBearerToken adds "authorization: Bearer <token>" header to every call.
+func WithBearerToken(token string) Option {
+	return WithMetadata("authorization", "Bearer "+token)
+}
+
+// WithDialOptions passes options to grpc.Dial, e.g. a custom dialer.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// SetOption changes a single Set call.
+type SetOption func(*setOptions)
+
+type setOptions struct {
+	ttl   time.Duration
+	flags uint32
+	mode  setMode
+}
+
+type setMode int
+
+const (
+	modeSet setMode = iota
+	modeAdd
+	modeReplace
+)
+
+// WithTTL makes the key expire after ttl.
+func WithTTL(ttl time.Duration) SetOption {
+	return func(o *setOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithFlags stores opaque flags with the value.
+func WithFlags(flags uint32) SetOption {
+	return func(o *setOptions) {
+		o.flags = flags
+	}
+}
+
+// IfNotExists writes the value only if the key does not exist, ErrExists is returned otherwise.
+func IfNotExists() SetOption {
+	return func(o *setOptions) {
+		o.mode = modeAdd
+	}
+}
+
+// IfExists writes the value only if the key exists, ErrNotFound is returned otherwise.
+func IfExists() SetOption {
+	return func(o *setOptions) {
+		o.mode = modeReplace
+	}
+}
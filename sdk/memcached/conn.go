@@ -8,7 +8,9 @@ import (
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -16,19 +18,61 @@ import (
 var (
 	delimiter = []byte("\r\n")
 
-	storedResp   = []byte("STORED\r\n")
-	endResp      = []byte("END\r\n")
-	deletedResp  = []byte("DELETED\r\n")
-	notFoundResp = []byte("NOT_FOUND\r\n")
-
-	valueHeaderRE = regexp.MustCompile(`^(?m)VALUE [a-zA-Z0-9_]+ \d+ (\d+)( \d+){0,1}\r\n$`) // value in first `()` is length.
+	storedResp    = []byte("STORED\r\n")
+	notStoredResp = []byte("NOT_STORED\r\n")
+	existsResp    = []byte("EXISTS\r\n")
+	endResp       = []byte("END\r\n")
+	deletedResp   = []byte("DELETED\r\n")
+	notFoundResp  = []byte("NOT_FOUND\r\n")
+	touchedResp   = []byte("TOUCHED\r\n")
+	metaMissResp  = []byte("EN\r\n")
+	versionPrefix = []byte("VERSION ")
+	statPrefix    = []byte("STAT ")
+
+	nonNumericResp = []byte("CLIENT_ERROR cannot increment or decrement non-numeric value\r\n")
+	tooLargeResp   = []byte("SERVER_ERROR object too large for cache\r\n")
+
+	valueHeaderRE = regexp.MustCompile(`^(?m)VALUE (\S+) (\d+) (\d+)(?: (\d+)){0,1}\r\n$`) // values in `()` are key, flags, length and cas.
 )
 
 const (
-	maxKeySize   = 255
+	maxKeySize   = 250
 	maxValueSize = 1024 * 1024
+
+	// maxKeysPerGet limits the length of a single retrieval command line, bigger batches are split into several
+	// commands which are still sent in one round trip.
+	maxKeysPerGet = 100
+
+	// maxPipelineSize limits the number of commands written before reading their responses, so that neither side
+	// blocks on full socket buffers.
+	maxPipelineSize = 128
+
+	// maxRelativeExpiry is the longest ttl memcached treats as relative, larger exptimes are unix timestamps.
+	maxRelativeExpiry = 30 * 24 * time.Hour
 )
 
+type Item struct {
+	Value []byte
+	Flags uint32
+	CAS   uint64
+}
+
+// ItemMeta is returned by the meta get command which requires memcached 1.6 or newer.
+type ItemMeta struct {
+	Size  int
+	Flags uint32
+	CAS   uint64
+	TTL   time.Duration // remaining ttl, negative if the item never expires.
+}
+
+// Entry is a single write of a batch.
+type Entry struct {
+	Key   string
+	Value []byte
+	Flags uint32
+	TTL   time.Duration
+}
+
 type Conn struct {
 	mu sync.Mutex
 	c  net.Conn
@@ -38,7 +82,7 @@ type Conn struct {
 func NewConnWithAddress(addr string) (*Conn, error) {
 	c, err := net.Dial("tcp", addr)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to dial")
+		return nil, errors.Wrap(connError(err), "failed to dial")
 	}
 
 	return NewConn(c), nil
@@ -47,8 +91,8 @@ func NewConnWithAddress(addr string) (*Conn, error) {
 func NewConn(conn net.Conn) *Conn {
 	return &Conn{
 		rw: bufio.NewReadWriter(
-			bufio.NewReaderSize(conn, maxKeySize+maxValueSize+1024),
-			bufio.NewWriterSize(conn, maxKeySize+maxValueSize+1024),
+			bufio.NewReaderSize(markedConn{conn}, maxKeySize+maxValueSize+1024),
+			bufio.NewWriterSize(markedConn{conn}, maxKeySize+maxValueSize+1024),
 		),
 		c: conn,
 	}
@@ -58,101 +102,510 @@ func (c *Conn) Close() error {
 	return c.c.Close()
 }
 
-func (c *Conn) Set(key string, value []byte) error {
+func (c *Conn) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	return c.store(set(key, flags, exptime(ttl), len(value)), value)
+}
+
+func (c *Conn) Add(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	return c.store(add(key, flags, exptime(ttl), len(value)), value)
+}
+
+func (c *Conn) Replace(key string, value []byte, flags uint32, ttl time.Duration) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	return c.store(replace(key, flags, exptime(ttl), len(value)), value)
+}
+
+// Append adds value after the existing value of key. ErrNotStored is returned when the key does not exist.
+func (c *Conn) Append(key string, value []byte) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	return c.store(appendCmd(key, len(value)), value)
+}
+
+// Prepend adds value before the existing value of key. ErrNotStored is returned when the key does not exist.
+func (c *Conn) Prepend(key string, value []byte) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	return c.store(prependCmd(key, len(value)), value)
+}
+
+func (c *Conn) CompareAndSwap(key string, value []byte, flags uint32, ttl time.Duration, cas uint64) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	_, err := c.rw.WriteString(set(key, 0, 0, len(value)))
+	err := c.write([]byte(compareAndSwap(key, flags, exptime(ttl), len(value), cas)), value)
 	if err != nil {
 		return err
 	}
-	_, err = c.rw.Write(delimiter)
+
+	resp, err := c.rw.ReadBytes('\n')
 	if err != nil {
 		return err
 	}
-	_, err = c.rw.Write(value)
+
+	switch {
+	case bytes.Equal(resp, storedResp):
+		return nil
+	case bytes.Equal(resp, existsResp):
+		return ErrCASConflict
+	case bytes.Equal(resp, notFoundResp):
+		return ErrNotFound
+	case bytes.Equal(resp, tooLargeResp):
+		return ErrTooLarge
+	}
+
+	return ErrNotStored
+}
+
+func (c *Conn) Get(key string) ([]byte, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(get(key)))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = c.rw.Write(delimiter)
+
+	items, err := c.readItems()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	item, ok := items[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return item.Value, nil
+}
+
+func (c *Conn) Gets(key string) (Item, error) {
+	if !validKey(key) {
+		return Item{}, ErrInvalidKey
 	}
-	err = c.rw.Flush()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(gets(key)))
 	if err != nil {
-		return err
+		return Item{}, err
 	}
 
-	resp, err := c.rw.ReadBytes('\n')
+	items, err := c.readItems()
 	if err != nil {
-		return err
+		return Item{}, err
 	}
 
-	if !bytes.Equal(resp, storedResp) {
-		return ErrNotStored
+	item, ok := items[key]
+	if !ok {
+		return Item{}, ErrNotFound
 	}
 
-	return nil
+	return item, nil
 }
 
-func (c *Conn) Get(key string) ([]byte, error) {
+// GetAndTouch fetches key and updates its expiration time.
+func (c *Conn) GetAndTouch(key string, ttl time.Duration) (Item, error) {
+	if !validKey(key) {
+		return Item{}, ErrInvalidKey
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	_, err := c.rw.WriteString(get(key))
+	err := c.write([]byte(gats(key, exptime(ttl))))
 	if err != nil {
-		return nil, err
+		return Item{}, err
 	}
-	_, err = c.rw.Write(delimiter)
+
+	items, err := c.readItems()
 	if err != nil {
-		return nil, err
+		return Item{}, err
+	}
+
+	item, ok := items[key]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+
+	return item, nil
+}
+
+// GetMulti fetches all keys in a single round trip. Missing keys are absent from the result.
+func (c *Conn) GetMulti(keys ...string) (map[string]Item, error) {
+	if len(keys) == 0 {
+		return map[string]Item{}, nil
+	}
+
+	for _, key := range keys {
+		if !validKey(key) {
+			return nil, ErrInvalidKey
+		}
 	}
-	err = c.rw.Flush()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cmds := make([][]byte, 0, (len(keys)+maxKeysPerGet-1)/maxKeysPerGet)
+	for i := 0; i < len(keys); i += maxKeysPerGet {
+		end := i + maxKeysPerGet
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		cmds = append(cmds, []byte(gets(keys[i:end]...)))
+	}
+
+	err := c.write(cmds...)
 	if err != nil {
 		return nil, err
 	}
 
-	header, err := c.rw.ReadBytes('\n')
+	res := make(map[string]Item, len(keys))
+	for range cmds {
+		items, err := c.readItems()
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range items {
+			res[k] = v
+		}
+	}
+
+	return res, nil
+}
+
+func (c *Conn) Delete(key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(delete(key)))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read bytes")
+		return err
 	}
 
-	if bytes.Equal(header, endResp) {
-		return nil, ErrNotFound
+	resp, err := c.rw.ReadBytes('\n')
+	if err != nil {
+		return err
 	}
 
-	matches := valueHeaderRE.FindSubmatch(header)
-	if len(matches) == 0 {
-		return nil, ErrInvalidValueHeader
+	return deleteResult(resp)
+}
+
+func (c *Conn) MetaGet(key string) (ItemMeta, error) {
+	if !validKey(key) {
+		return ItemMeta{}, ErrInvalidKey
 	}
 
-	length, err := strconv.Atoi(string(matches[1]))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(metaGet(key)))
 	if err != nil {
-		panic(err) // should have been handled with regex.
+		return ItemMeta{}, err
 	}
 
-	res := make([]byte, length+7) // 7 is for `\r\nEND\r\n`.
-	_, err = io.ReadFull(c.rw, res)
+	resp, err := c.rw.ReadBytes('\n')
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read value")
+		return ItemMeta{}, err
+	}
+
+	if bytes.Equal(resp, metaMissResp) {
+		return ItemMeta{}, ErrNotFound
 	}
 
-	return res[:len(res)-7], nil
+	return parseItemMeta(resp)
 }
 
-func (c *Conn) Delete(key string) error {
+// parseItemMeta parses "HD s<size> t<ttl> c<cas> f<flags>\r\n" response of meta get.
+func parseItemMeta(resp []byte) (ItemMeta, error) {
+	fields := strings.Fields(string(resp))
+	if len(fields) == 0 || fields[0] != "HD" {
+		return ItemMeta{}, errors.Wrap(ErrUnknownResponse, "failed to meta get")
+	}
+
+	var meta ItemMeta
+	for _, f := range fields[1:] {
+		if len(f) < 2 {
+			return ItemMeta{}, errors.Wrap(ErrUnknownResponse, "failed to meta get")
+		}
+
+		v, err := strconv.ParseInt(f[1:], 10, 64)
+		if err != nil {
+			return ItemMeta{}, errors.Wrap(ErrUnknownResponse, "failed to meta get")
+		}
+
+		switch f[0] {
+		case 's':
+			meta.Size = int(v)
+		case 't':
+			meta.TTL = time.Duration(v) * time.Second
+		case 'c':
+			meta.CAS = uint64(v)
+		case 'f':
+			meta.Flags = uint32(v)
+		}
+	}
+
+	return meta, nil
+}
+
+// Version returns the version of memcached, it is a cheap round trip to check that the connection works.
+func (c *Conn) Version() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte("version"))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.rw.ReadBytes('\n')
+	if err != nil {
+		return "", err
+	}
+
+	if !bytes.HasPrefix(resp, versionPrefix) || !bytes.HasSuffix(resp, delimiter) {
+		return "", errors.Wrap(ErrUnknownResponse, "failed to get version")
+	}
+
+	return string(resp[len(versionPrefix) : len(resp)-len(delimiter)]), nil
+}
+
+// Stats returns general-purpose statistics of memcached by name, e.g. "curr_items" or "get_hits".
+func (c *Conn) Stats() (map[string]string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	_, err := c.rw.WriteString(delete(key))
+	err := c.write([]byte("stats"))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]string)
+	for {
+		resp, err := c.rw.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(resp, endResp) {
+			return stats, nil
+		}
+
+		if !bytes.HasPrefix(resp, statPrefix) || !bytes.HasSuffix(resp, delimiter) {
+			return nil, errors.Wrap(ErrUnknownResponse, "failed to get stats")
+		}
+		name, value, ok := strings.Cut(string(resp[len(statPrefix):len(resp)-len(delimiter)]), " ")
+		if !ok {
+			return nil, errors.Wrap(ErrUnknownResponse, "failed to get stats")
+		}
+		stats[name] = value
+	}
+}
+
+func (c *Conn) Touch(key string, ttl time.Duration) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(touch(key, exptime(ttl))))
 	if err != nil {
 		return err
 	}
-	_, err = c.rw.Write(delimiter)
+
+	resp, err := c.rw.ReadBytes('\n')
 	if err != nil {
 		return err
 	}
-	err = c.rw.Flush()
+
+	switch {
+	case bytes.Equal(resp, touchedResp):
+		return nil
+	case bytes.Equal(resp, notFoundResp):
+		return ErrNotFound
+	}
+
+	return errors.Wrap(ErrUnknownResponse, "failed to touch")
+}
+
+// Increment adds delta to a decimal value stored under key. The value wraps around on overflow.
+func (c *Conn) Increment(key string, delta uint64) (uint64, error) {
+	if !validKey(key) {
+		return 0, ErrInvalidKey
+	}
+
+	return c.arithmetic(incr(key, delta))
+}
+
+// Decrement subtracts delta from a decimal value stored under key. The value never goes below zero.
+func (c *Conn) Decrement(key string, delta uint64) (uint64, error) {
+	if !validKey(key) {
+		return 0, ErrInvalidKey
+	}
+
+	return c.arithmetic(decr(key, delta))
+}
+
+func (c *Conn) arithmetic(cmd string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(cmd))
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.rw.ReadBytes('\n')
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case bytes.Equal(resp, notFoundResp):
+		return 0, ErrNotFound
+	case bytes.Equal(resp, nonNumericResp):
+		return 0, ErrNonNumericValue
+	}
+
+	v, err := strconv.ParseUint(string(bytes.TrimSuffix(resp, delimiter)), 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(ErrUnknownResponse, "failed to parse counter value")
+	}
+
+	return v, nil
+}
+
+// SetMulti pipelines set commands for all entries. The returned slice holds an error for every entry, the second
+// return value is set when the connection failed and the remaining entries were not processed.
+func (c *Conn) SetMulti(entries []Entry) ([]error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := make([]error, len(entries))
+	valid := make([]int, 0, len(entries)) // indexes of entries that are sent.
+	for i, e := range entries {
+		if validKey(e.Key) {
+			valid = append(valid, i)
+		} else {
+			errs[i] = ErrInvalidKey
+		}
+	}
+
+	for i := 0; i < len(valid); i += maxPipelineSize {
+		end := i + maxPipelineSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[i:end]
+
+		lines := make([][]byte, 0, 2*len(batch))
+		for _, idx := range batch {
+			e := entries[idx]
+			lines = append(lines, []byte(set(e.Key, e.Flags, exptime(e.TTL), len(e.Value))), e.Value)
+		}
+
+		err := c.write(lines...)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range batch {
+			resp, err := c.rw.ReadBytes('\n')
+			if err != nil {
+				return nil, err
+			}
+
+			errs[valid[i+j]] = storeResult(resp)
+		}
+	}
+
+	return errs, nil
+}
+
+// DeleteMulti pipelines delete commands for all keys, see SetMulti for the meaning of return values.
+func (c *Conn) DeleteMulti(keys ...string) ([]error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := make([]error, len(keys))
+	valid := make([]int, 0, len(keys)) // indexes of keys that are sent.
+	for i, key := range keys {
+		if validKey(key) {
+			valid = append(valid, i)
+		} else {
+			errs[i] = ErrInvalidKey
+		}
+	}
+
+	for i := 0; i < len(valid); i += maxPipelineSize {
+		end := i + maxPipelineSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[i:end]
+
+		lines := make([][]byte, 0, len(batch))
+		for _, idx := range batch {
+			lines = append(lines, []byte(delete(keys[idx])))
+		}
+
+		err := c.write(lines...)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range batch {
+			resp, err := c.rw.ReadBytes('\n')
+			if err != nil {
+				return nil, err
+			}
+
+			errs[valid[i+j]] = deleteResult(resp)
+		}
+	}
+
+	return errs, nil
+}
+
+// store sends a storage command and checks that the value was stored.
+func (c *Conn) store(cmd string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write([]byte(cmd), value)
 	if err != nil {
 		return err
 	}
@@ -162,6 +615,89 @@ func (c *Conn) Delete(key string) error {
 		return err
 	}
 
+	return storeResult(resp)
+}
+
+// write sends every line followed by a delimiter and flushes the buffer.
+func (c *Conn) write(lines ...[]byte) error {
+	for _, line := range lines {
+		_, err := c.rw.Write(line)
+		if err != nil {
+			return err
+		}
+		_, err = c.rw.Write(delimiter)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.rw.Flush()
+}
+
+// readItems reads a response to a retrieval command: any number of values terminated with END.
+func (c *Conn) readItems() (map[string]Item, error) {
+	items := make(map[string]Item)
+	for {
+		header, err := c.rw.ReadBytes('\n')
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read bytes")
+		}
+
+		if bytes.Equal(header, endResp) {
+			return items, nil
+		}
+
+		matches := valueHeaderRE.FindSubmatch(header)
+		if len(matches) == 0 {
+			return nil, ErrInvalidValueHeader
+		}
+
+		flags, err := strconv.ParseUint(string(matches[2]), 10, 32)
+		if err != nil {
+			return nil, ErrInvalidValueHeader
+		}
+
+		length, err := strconv.Atoi(string(matches[3]))
+		if err != nil {
+			panic(err) // should have been handled with regex.
+		}
+
+		var cas uint64
+		if len(matches[4]) > 0 {
+			cas, err = strconv.ParseUint(string(matches[4]), 10, 64)
+			if err != nil {
+				return nil, ErrInvalidValueHeader
+			}
+		}
+
+		value := make([]byte, length+len(delimiter))
+		_, err = io.ReadFull(c.rw, value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read value")
+		}
+
+		items[string(matches[1])] = Item{
+			Value: value[:length],
+			Flags: uint32(flags),
+			CAS:   cas,
+		}
+	}
+}
+
+func storeResult(resp []byte) error {
+	switch {
+	case bytes.Equal(resp, storedResp):
+		return nil
+	case bytes.Equal(resp, notStoredResp):
+		return ErrNotStored
+	case bytes.Equal(resp, tooLargeResp):
+		return ErrTooLarge
+	}
+
+	return errors.Wrap(ErrUnknownResponse, "failed to store")
+}
+
+func deleteResult(resp []byte) error {
 	switch {
 	case bytes.Equal(resp, deletedResp):
 		return nil
@@ -172,14 +708,100 @@ func (c *Conn) Delete(key string) error {
 	return errors.Wrap(ErrUnknownResponse, "failed to delete")
 }
 
-func set(key string, meta, expiry, length int) string {
-	return fmt.Sprintf("set %s %d %d %d", key, meta, expiry, length)
+// validKey reports whether key can be sent in a text protocol command.
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > maxKeySize {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
+// markedConn marks errors of conn with connError. Connection closed by memcached is reported as unavailable too.
+type markedConn struct {
+	net.Conn
+}
+
+func (c markedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	return n, connError(err)
+}
+
+func (c markedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	return n, connError(err)
+}
+
+func set(key string, flags uint32, expiry int64, length int) string {
+	return fmt.Sprintf("set %s %d %d %d", key, flags, expiry, length)
+}
+
+func add(key string, flags uint32, expiry int64, length int) string {
+	return fmt.Sprintf("add %s %d %d %d", key, flags, expiry, length)
+}
+
+func replace(key string, flags uint32, expiry int64, length int) string {
+	return fmt.Sprintf("replace %s %d %d %d", key, flags, expiry, length)
+}
+
+func appendCmd(key string, length int) string {
+	return fmt.Sprintf("append %s 0 0 %d", key, length) // flags and exptime are ignored by memcached.
+}
+
+func prependCmd(key string, length int) string {
+	return fmt.Sprintf("prepend %s 0 0 %d", key, length) // flags and exptime are ignored by memcached.
+}
+
+func compareAndSwap(key string, flags uint32, expiry int64, length int, cas uint64) string {
+	return fmt.Sprintf("cas %s %d %d %d %d", key, flags, expiry, length, cas)
+}
+
+func incr(key string, delta uint64) string {
+	return fmt.Sprintf("incr %s %d", key, delta)
+}
+
+func decr(key string, delta uint64) string {
+	return fmt.Sprintf("decr %s %d", key, delta)
 }
 
 func get(key string) string {
 	return "get " + key
 }
 
+func gets(keys ...string) string {
+	return "gets " + strings.Join(keys, " ")
+}
+
+func gats(key string, expiry int64) string {
+	return fmt.Sprintf("gats %d %s", expiry, key)
+}
+
+func metaGet(key string) string {
+	return fmt.Sprintf("mg %s s t c f", key)
+}
+
+func touch(key string, expiry int64) string {
+	return fmt.Sprintf("touch %s %d", key, expiry)
+}
+
 func delete(key string) string {
 	return "delete " + key
 }
+
+func exptime(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+
+	if ttl > maxRelativeExpiry {
+		return time.Now().Add(ttl).Unix()
+	}
+
+	return int64((ttl + time.Second - 1) / time.Second) // round up, so that sub-second ttl does not mean "never expire".
+}
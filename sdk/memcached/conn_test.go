@@ -1,7 +1,12 @@
 package memcached
 
 import (
+	"errors"
+	"net"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -27,7 +32,25 @@ func TestConnHappyPaths(t *testing.T) {
 		copy(dst, []byte(r))
 		return len(r), nil
 	}).Times(1)
-	err := c.Set(key, val)
+	err := c.Set(key, val, 0, 0)
+	require.NoError(t, err)
+
+	nc.EXPECT().Write([]byte("set key 0 60 5\r\n12345\r\n")).Return(23, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	}).Times(1)
+	err = c.Set(key, val, 0, time.Minute)
+	require.NoError(t, err)
+
+	nc.EXPECT().Write([]byte("set key 42 0 5\r\n12345\r\n")).Return(23, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	}).Times(1)
+	err = c.Set(key, val, 42, 0)
 	require.NoError(t, err)
 
 	nc.EXPECT().Write([]byte("get key\r\n")).Return(9, nil).Times(1)
@@ -63,11 +86,398 @@ func TestConnHappyPaths(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestConnAddReplace(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("add key 0 0 5\r\n12345\r\n")).Return(22, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "NOT_STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err := c.Add("key", []byte("12345"), 0, 0)
+	require.ErrorIs(t, err, ErrNotStored)
+
+	nc.EXPECT().Write([]byte("replace key 0 0 5\r\n12345\r\n")).Return(26, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.Replace("key", []byte("12345"), 0, 0)
+	require.NoError(t, err)
+}
+
+func TestConnAppendPrepend(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("append key 0 0 3\r\nabc\r\n")).Return(23, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err := c.Append("key", []byte("abc"))
+	require.NoError(t, err)
+
+	nc.EXPECT().Write([]byte("prepend key 0 0 3\r\nabc\r\n")).Return(24, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "NOT_STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.Prepend("key", []byte("abc"))
+	require.ErrorIs(t, err, ErrNotStored)
+}
+
+func TestConnCompareAndSwap(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("gets key\r\n")).Return(10, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "VALUE key 7 5 42\r\n12345\r\nEND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	item, err := c.Gets("key")
+	require.NoError(t, err)
+	require.Equal(t, Item{Value: []byte("12345"), Flags: 7, CAS: 42}, item)
+
+	nc.EXPECT().Write([]byte("cas key 7 0 5 42\r\n54321\r\n")).Return(25, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.CompareAndSwap("key", []byte("54321"), 7, 0, 42)
+	require.NoError(t, err)
+
+	nc.EXPECT().Write([]byte("cas key 0 0 5 42\r\n54321\r\n")).Return(25, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "EXISTS\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.CompareAndSwap("key", []byte("54321"), 0, 0, 42)
+	require.ErrorIs(t, err, ErrCASConflict)
+
+	nc.EXPECT().Write([]byte("cas key 0 0 5 42\r\n54321\r\n")).Return(25, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "NOT_FOUND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.CompareAndSwap("key", []byte("54321"), 0, 0, 42)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestConnTouch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("touch key 60\r\n")).Return(14, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "TOUCHED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err := c.Touch("key", time.Minute)
+	require.NoError(t, err)
+
+	nc.EXPECT().Write([]byte("touch key 60\r\n")).Return(14, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "NOT_FOUND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.Touch("key", time.Minute)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	nc.EXPECT().Write([]byte("gats 60 key\r\n")).Return(13, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "VALUE key 0 5 7\r\n12345\r\nEND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	item, err := c.GetAndTouch("key", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, Item{Value: []byte("12345"), CAS: 7}, item)
+}
+
+func TestConnMetaGet(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("mg key s t c f\r\n")).Return(16, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "HD s5 t60 c7 f3\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	meta, err := c.MetaGet("key")
+	require.NoError(t, err)
+	require.Equal(t, ItemMeta{Size: 5, Flags: 3, CAS: 7, TTL: time.Minute}, meta)
+
+	nc.EXPECT().Write([]byte("mg key s t c f\r\n")).Return(16, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "HD s5 t-1 c7 f0\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	meta, err = c.MetaGet("key")
+	require.NoError(t, err)
+	require.Equal(t, ItemMeta{Size: 5, CAS: 7, TTL: -time.Second}, meta)
+
+	nc.EXPECT().Write([]byte("mg key s t c f\r\n")).Return(16, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "EN\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	_, err = c.MetaGet("key")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	nc.EXPECT().Write([]byte("mg key s t c f\r\n")).Return(16, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "ERROR\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	_, err = c.MetaGet("key")
+	require.ErrorIs(t, err, ErrUnknownResponse)
+}
+
+func TestConnVersion(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("version\r\n")).Return(9, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "VERSION 1.6.15\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	v, err := c.Version()
+	require.NoError(t, err)
+	require.Equal(t, "1.6.15", v)
+
+	nc.EXPECT().Write([]byte("version\r\n")).Return(9, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "ERROR\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	_, err = c.Version()
+	require.ErrorIs(t, err, ErrUnknownResponse)
+}
+
+func TestConnStats(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("stats\r\n")).Return(7, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STAT pid 1\r\nSTAT version 1.6.15\r\nSTAT curr_items 42\r\nEND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	stats, err := c.Stats()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"pid": "1", "version": "1.6.15", "curr_items": "42"}, stats)
+
+	nc.EXPECT().Write([]byte("stats\r\n")).Return(7, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "ERROR\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	_, err = c.Stats()
+	require.ErrorIs(t, err, ErrUnknownResponse)
+}
+
+func TestConnGetMulti(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("gets a b c\r\n")).Return(12, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "VALUE a 0 1 1\r\n1\r\nVALUE c 0 3 3\r\n333\r\nEND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	items, err := c.GetMulti("a", "b", "c")
+	require.NoError(t, err)
+	require.Equal(t, map[string]Item{
+		"a": {Value: []byte("1"), CAS: 1},
+		"c": {Value: []byte("333"), CAS: 3},
+	}, items)
+
+	items, err = c.GetMulti()
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestConnArithmetic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("incr key 5\r\n")).Return(12, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "15\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	v, err := c.Increment("key", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), v)
+
+	nc.EXPECT().Write([]byte("decr key 20\r\n")).Return(13, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "0\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	v, err = c.Decrement("key", 20)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), v)
+
+	nc.EXPECT().Write([]byte("incr missing 1\r\n")).Return(16, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "NOT_FOUND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	_, err = c.Increment("missing", 1)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	nc.EXPECT().Write([]byte("incr text 1\r\n")).Return(13, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "CLIENT_ERROR cannot increment or decrement non-numeric value\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	_, err = c.Increment("text", 1)
+	require.ErrorIs(t, err, ErrNonNumericValue)
+}
+
+func TestConnPipelining(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	nc.EXPECT().Write([]byte("set a 0 0 1\r\n1\r\nset b 0 60 1\r\n2\r\n")).Return(34, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\nNOT_STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	errs, err := c.SetMulti([]Entry{
+		{Key: "a", Value: []byte("1")},
+		{Key: "b", Value: []byte("2"), TTL: time.Minute},
+	})
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], ErrNotStored)
+
+	nc.EXPECT().Write([]byte("delete a\r\ndelete b\r\n")).Return(20, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "DELETED\r\nNOT_FOUND\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	errs, err = c.DeleteMulti("a", "b")
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], ErrNotFound)
+}
+
+func TestConnErrors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctrl := gomock.NewController(t)
+	nc := mocknet.NewMockConn(ctrl)
+	c := NewConn(nc)
+
+	for _, key := range []string{"", "with space", "new\nline", strings.Repeat("k", maxKeySize+1)} {
+		err := c.Set(key, []byte("1"), 0, 0)
+		require.ErrorIs(t, err, ErrInvalidKey)
+	}
+
+	nc.EXPECT().Write([]byte("set a 0 0 1\r\n1\r\n")).Return(16, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "STORED\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	errs, err := c.SetMulti([]Entry{
+		{Key: "bad key", Value: []byte("1")},
+		{Key: "a", Value: []byte("1")},
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, errs[0], ErrInvalidKey)
+	require.NoError(t, errs[1])
+
+	nc.EXPECT().Write([]byte("set key 0 0 1\r\n1\r\n")).Return(18, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).DoAndReturn(func(dst []byte) (n int, err error) {
+		r := "SERVER_ERROR object too large for cache\r\n"
+		copy(dst, []byte(r))
+		return len(r), nil
+	})
+	err = c.Set("key", []byte("1"), 0, 0)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	nc.EXPECT().Write([]byte("get key\r\n")).Return(9, nil).Times(1)
+	nc.EXPECT().Read(gomock.Any()).Return(0, os.ErrDeadlineExceeded)
+	_, err = c.Get("key")
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	require.True(t, errors.As(err, new(interface{ TimeoutErrorMarker() })))
+
+	nc.EXPECT().Write([]byte("get key\r\n")).Return(0, net.ErrClosed).Times(1)
+	_, err = c.Get("key")
+	require.ErrorIs(t, err, net.ErrClosed)
+	require.True(t, errors.As(err, new(interface{ UnavailableErrorMarker() })))
+}
+
 func TestValueHeaderRE(t *testing.T) {
 	require.True(t, valueHeaderRE.MatchString("VALUE key 0 0\r\n"))
 	require.True(t, valueHeaderRE.MatchString("VALUE key 123 123\r\n"))
 	require.True(t, valueHeaderRE.MatchString("VALUE key 324 2343 3423\r\n"))
 	require.True(t, valueHeaderRE.MatchString("VALUE key 0 0 0\r\n"))
+	require.True(t, valueHeaderRE.MatchString("VALUE user:1/session 0 0 0\r\n"))
 
 	require.False(t, valueHeaderRE.MatchString("VALUE key 0"))
 	require.False(t, valueHeaderRE.MatchString("VALUE key 0 0"))
@@ -75,4 +485,18 @@ func TestValueHeaderRE(t *testing.T) {
 	require.False(t, valueHeaderRE.MatchString("VALUE key 0 0 0"))
 	require.False(t, valueHeaderRE.MatchString("VALUE key 0 0 0\n"))
 	require.False(t, valueHeaderRE.MatchString("sdfsdf"))
+
+	matches := valueHeaderRE.FindStringSubmatch("VALUE key 1 2 3\r\n")
+	require.Equal(t, []string{"VALUE key 1 2 3\r\n", "key", "1", "2", "3"}, matches)
+}
+
+func TestExptime(t *testing.T) {
+	require.Equal(t, int64(0), exptime(0))
+	require.Equal(t, int64(0), exptime(-time.Second))
+	require.Equal(t, int64(1), exptime(time.Millisecond))
+	require.Equal(t, int64(60), exptime(time.Minute))
+	require.Equal(t, int64(2592000), exptime(maxRelativeExpiry))
+
+	ts := exptime(maxRelativeExpiry + time.Hour)
+	require.InDelta(t, time.Now().Add(maxRelativeExpiry+time.Hour).Unix(), ts, 1)
 }
@@ -1,16 +1,55 @@
 package memcached
 
-import "errors"
+import (
+	"errors"
+	"net"
+)
 
 var (
-	ErrNotStored          = errors.New("not stored")
+	ErrNotStored          = notStoredError{errors.New("not stored")}
 	ErrInvalidValueHeader = errors.New("invalid value header")
 	ErrNotFound           = notFoundError{errors.New("not found")}
 	ErrUnknownResponse    = unknownError{errors.New("unknown response")}
+	ErrCASConflict        = conflictError{errors.New("item was modified since it was fetched")}
+	ErrNonNumericValue    = nonNumericError{errors.New("cannot increment or decrement non-numeric value")}
+	ErrTooLarge           = tooLargeError{errors.New("object too large for cache")}
+	ErrInvalidKey         = invalidKeyError{errors.New("key must be 1 to 250 bytes long without spaces and control characters")}
 )
 
 type notFoundError struct{ error }
 type unknownError struct{ error }
+type conflictError struct{ error }
+type notStoredError struct{ error }
+type nonNumericError struct{ error }
+type tooLargeError struct{ error }
+type invalidKeyError struct{ error }
+type unavailableError struct{ error }
+type timeoutError struct{ error }
+
+func (notFoundError) NotFoundErrorMarker()       {}
+func (unknownError) UnknownErrorMarker()         {}
+func (conflictError) ConflictErrorMarker()       {}
+func (notStoredError) NotStoredErrorMarker()     {}
+func (nonNumericError) NonNumericErrorMarker()   {}
+func (tooLargeError) TooLargeErrorMarker()       {}
+func (invalidKeyError) InvalidKeyErrorMarker()   {}
+func (unavailableError) UnavailableErrorMarker() {}
+func (timeoutError) TimeoutErrorMarker()         {}
+
+func (e unavailableError) Unwrap() error { return e.error }
+func (e timeoutError) Unwrap() error     { return e.error }
+
+// connError marks errors of the underlying connection, so that callers can tell timeouts and lost connections from
+// memcached responses.
+func connError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return timeoutError{err}
+	}
 
-func (notFoundError) NotFoundErrorMarker() {}
-func (unknownError) UnknownErrorMarker()   {}
+	return unavailableError{err}
+}
@@ -2,6 +2,7 @@ package memcached
 
 import (
 	"net"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -37,7 +38,7 @@ func NewPoolWithAddress(addr string, size int) (pool *Pool, err error) {
 	for i := 0; i < size; i++ {
 		c, err := net.Dial("tcp", addr)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to dial")
+			return nil, errors.Wrap(connError(err), "failed to dial")
 		}
 
 		semaphore <- NewConn(c)
@@ -63,11 +64,60 @@ func (p *Pool) Close() error {
 	return eg.Wait()
 }
 
-func (p *Pool) Set(key string, value []byte) error {
+func (p *Pool) Set(key string, value []byte, flags uint32, ttl time.Duration) error {
 	c := <-p.semaphore
 	defer func() { p.semaphore <- c }()
 
-	return c.Set(key, value)
+	return c.Set(key, value, flags, ttl)
+}
+
+func (p *Pool) Add(key string, value []byte, flags uint32, ttl time.Duration) error {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Add(key, value, flags, ttl)
+}
+
+func (p *Pool) Replace(key string, value []byte, flags uint32, ttl time.Duration) error {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Replace(key, value, flags, ttl)
+}
+
+func (p *Pool) Append(key string, value []byte) error {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Append(key, value)
+}
+
+func (p *Pool) Prepend(key string, value []byte) error {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Prepend(key, value)
+}
+
+func (p *Pool) CompareAndSwap(key string, value []byte, flags uint32, ttl time.Duration, cas uint64) error {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.CompareAndSwap(key, value, flags, ttl, cas)
+}
+
+func (p *Pool) Increment(key string, delta uint64) (uint64, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Increment(key, delta)
+}
+
+func (p *Pool) Decrement(key string, delta uint64) (uint64, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Decrement(key, delta)
 }
 
 func (p *Pool) Get(key string) ([]byte, error) {
@@ -77,6 +127,69 @@ func (p *Pool) Get(key string) ([]byte, error) {
 	return c.Get(key)
 }
 
+func (p *Pool) Gets(key string) (Item, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Gets(key)
+}
+
+func (p *Pool) GetAndTouch(key string, ttl time.Duration) (Item, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.GetAndTouch(key, ttl)
+}
+
+func (p *Pool) MetaGet(key string) (ItemMeta, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.MetaGet(key)
+}
+
+func (p *Pool) Version() (string, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Version()
+}
+
+func (p *Pool) Stats() (map[string]string, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Stats()
+}
+
+func (p *Pool) Touch(key string, ttl time.Duration) error {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.Touch(key, ttl)
+}
+
+func (p *Pool) GetMulti(keys ...string) (map[string]Item, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.GetMulti(keys...)
+}
+
+func (p *Pool) SetMulti(entries []Entry) ([]error, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.SetMulti(entries)
+}
+
+func (p *Pool) DeleteMulti(keys ...string) ([]error, error) {
+	c := <-p.semaphore
+	defer func() { p.semaphore <- c }()
+
+	return c.DeleteMulti(keys...)
+}
+
 func (p *Pool) Delete(key string) error {
 	c := <-p.semaphore
 	defer func() { p.semaphore <- c }()
@@ -2,6 +2,7 @@ package memcached
 
 import (
 	"errors"
+	"net"
 	"syscall"
 	"testing"
 
@@ -21,13 +22,23 @@ func TestPoolWithRealServer(t *testing.T) {
 	key := "key"
 	val := []byte("12345")
 
-	err = pool.Set(key, val)
+	err = pool.Set(key, val, 0, 0)
 	require.NoError(t, err)
 
 	v, err := pool.Get("key")
 	require.NoError(t, err)
 	require.Equal(t, val, v)
 
+	item, err := pool.Gets("key")
+	require.NoError(t, err)
+	require.Equal(t, val, item.Value)
+
+	err = pool.CompareAndSwap(key, []byte("54321"), 0, 0, item.CAS)
+	require.NoError(t, err)
+
+	err = pool.CompareAndSwap(key, []byte("54321"), 0, 0, item.CAS)
+	require.ErrorIs(t, err, ErrCASConflict)
+
 	err = pool.Delete("key")
 	require.NoError(t, err)
 
@@ -37,3 +48,13 @@ func TestPoolWithRealServer(t *testing.T) {
 	err = pool.Close()
 	require.NoError(t, err)
 }
+
+func TestPoolDialError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	_, err = NewPoolWithAddress(l.Addr().String(), 2)
+	require.ErrorIs(t, err, syscall.ECONNREFUSED)
+	require.True(t, errors.As(err, new(interface{ UnavailableErrorMarker() })))
+}